@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"chenizz/internal/controllers/internal"
+	"chenizz/internal/interfaces"
+)
+
+type AnalysisController struct {
+	interfaces.IAnalysisService
+}
+
+func (a AnalysisController) AnalyzeGame(w http.ResponseWriter, r *http.Request) {
+	p, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	params := internal.AnalyzeGameParams{}
+	err = json.Unmarshal(p, &params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	if err := validateDepth(params.Depth); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	resp, err := a.IAnalysisService.AnalyzeGame(r.Context(), params.PGN, params.Depth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// depth zero asks for the default one
+func validateDepth(depth int) error {
+	if depth < 0 || depth > interfaces.MaxAnalysisDepth {
+		return fmt.Errorf("depth must be between 0 and %d", interfaces.MaxAnalysisDepth)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chenizz/internal/controllers/internal"
+	"chenizz/internal/services/mocks"
+	"chenizz/internal/viewmodels"
+)
+
+func Test_AnalyzeGame_ValidGame(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+
+	serviceResponse := viewmodels.GameAnalysisResponse{
+		White: viewmodels.PlayerAnalysisResponse{Name: "alice", ACPL: 12.5, Accuracy: 91.2, Classifications: map[string]int{"best": 1}},
+		Black: viewmodels.PlayerAnalysisResponse{Name: "bob", Classifications: map[string]int{}},
+		Moves: []viewmodels.MoveAnalysisResponse{{
+			Ply: 1, Color: "w", Move: "e2e4", SAN: "e4", BestMove: "e2e4",
+			EvalBefore: viewmodels.EvalResponse{Centipawns: 20}, EvalAfter: viewmodels.EvalResponse{Centipawns: 25},
+			Accuracy: 100, Classification: "best",
+		}},
+	}
+
+	serviceMock := mocks.AnalysisServiceMock{}
+	serviceMock.PatchAnalyzeGame(serviceResponse, nil)
+
+	controller := AnalysisController{serviceMock}
+
+	body, _ := json.Marshal(internal.AnalyzeGameParams{PGN: "1. e4 *", Depth: 10})
+	req, err := http.NewRequest("POST", "/game/chess/analyze", bytes.NewBuffer(body))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.AnalyzeGame)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+	resp := viewmodels.GameAnalysisResponse{}
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	// Assert
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(serviceResponse, resp)
+}
+
+func Test_AnalyzeGame_ServiceError(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+
+	serviceMock := mocks.AnalysisServiceMock{}
+	serviceMock.PatchAnalyzeGame(viewmodels.GameAnalysisResponse{}, fmt.Errorf("error in service"))
+
+	controller := AnalysisController{serviceMock}
+
+	req, err := http.NewRequest("POST", "/game/chess/analyze", bytes.NewBufferString(`{"pgn": "1. e5 *"}`))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.AnalyzeGame)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal("{\"error\":\"error in service\"}\n", rr.Body.String())
+}
+
+func Test_AnalyzeGame_InvalidBody(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	controller := AnalysisController{mocks.AnalysisServiceMock{}}
+
+	req, err := http.NewRequest("POST", "/game/chess/analyze", bytes.NewBufferString("not json"))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.AnalyzeGame)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func Test_AnalyzeGame_DepthTooDeep(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	controller := AnalysisController{mocks.AnalysisServiceMock{}}
+
+	req, err := http.NewRequest("POST", "/game/chess/analyze", bytes.NewBufferString(`{"pgn": "1. e4 *", "depth": 60}`))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.AnalyzeGame)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusBadRequest, rr.Code)
+	assert.Equal("{\"error\":\"depth must be between 0 and 30\"}\n", rr.Body.String())
+}
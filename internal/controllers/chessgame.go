@@ -30,7 +30,7 @@ func (c ChessGameController) MakeMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := c.IChessGameService.MakeMove(params.Move, params.FEN)
+	resp, err := c.IChessGameService.MakeMove(params.Move, params.FEN, params.Variant, params.History...)
 	if err != nil {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		json.NewEncoder(w).Encode(errorResponse(err))
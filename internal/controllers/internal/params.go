@@ -3,4 +3,20 @@ package internal
 type MakeMoveParams struct {
 	Move string `json:"move"`
 	FEN  string `json:"fen"`
+	// rules game is played with, like "Three-check". Standard if empty
+	Variant string `json:"variant"`
+	// FENs of positions reached before FEN, oldest first
+	History []string `json:"history"`
+}
+
+type AnalyzeGameParams struct {
+	// plain-text PGN, only its first game is analyzed
+	PGN string `json:"pgn"`
+	// depth of the search of every position, a default one is used if it is zero
+	Depth int `json:"depth"`
+}
+
+type ParsePGNParams struct {
+	// plain-text PGN with one or more games
+	PGN string `json:"pgn"`
 }
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"chenizz/internal/controllers/internal"
+	"chenizz/internal/interfaces"
+)
+
+type PGNController struct {
+	interfaces.IPGNService
+}
+
+// ParsePGN answers the games of a PGN with their move trees, and the errors
+// of the games that could not be parsed
+func (c PGNController) ParsePGN(w http.ResponseWriter, r *http.Request) {
+	p, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	params := internal.ParsePGNParams{}
+	err = json.Unmarshal(p, &params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	resp, err := c.IPGNService.ParsePGN(params.PGN)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chenizz/internal/controllers/internal"
+	"chenizz/internal/services/mocks"
+	"chenizz/internal/viewmodels"
+)
+
+func Test_ParsePGN_ValidPGN(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+
+	serviceResponse := viewmodels.PGNResponse{
+		Games: []viewmodels.PGNGameResponse{{
+			White: "alice",
+			Moves: []string{"e2e4"},
+			Tree: viewmodels.MoveNodeResponse{
+				FEN: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+				Children: []viewmodels.MoveNodeResponse{
+					{SAN: "e4", UCI: "e2e4", FEN: "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", Comments: []string{"best"}},
+				},
+			},
+		}},
+		Errors: []viewmodels.PGNErrorResponse{{Game: 2, Line: 3, Column: 4, Message: "e5 is not a legal move"}},
+	}
+
+	serviceMock := mocks.PGNServiceMock{}
+	serviceMock.PatchParsePGN(serviceResponse, nil)
+
+	controller := PGNController{serviceMock}
+
+	body, _ := json.Marshal(internal.ParsePGNParams{PGN: "1. e4 {best} *"})
+	req, err := http.NewRequest("POST", "/game/chess/pgn", bytes.NewBuffer(body))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.ParsePGN)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+	resp := viewmodels.PGNResponse{}
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	// Assert
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(serviceResponse, resp)
+}
+
+func Test_ParsePGN_ServiceError(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+
+	serviceMock := mocks.PGNServiceMock{}
+	serviceMock.PatchParsePGN(viewmodels.PGNResponse{}, fmt.Errorf("error in service"))
+
+	controller := PGNController{serviceMock}
+
+	req, err := http.NewRequest("POST", "/game/chess/pgn", bytes.NewBufferString(`{"pgn": "1. e4 *"}`))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.ParsePGN)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal("{\"error\":\"error in service\"}\n", rr.Body.String())
+}
+
+func Test_ParsePGN_InvalidBody(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	controller := PGNController{mocks.PGNServiceMock{}}
+
+	req, err := http.NewRequest("POST", "/game/chess/pgn", bytes.NewBufferString("not json"))
+	if err != nil {
+		t.Errorf("error calling http.NewRequest: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(controller.ParsePGN)
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"chenizz/internal/interfaces"
+)
+
+type ReportController struct {
+	interfaces.IReportService
+}
+
+const (
+	// days of games a report has if they are not given
+	defaultReportDays = 30
+
+	// most days of games a report can have, every game is analyzed in the request
+	maxReportDays = 90
+)
+
+// characters Lichess allows in user names
+var userNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PlayerReport answers the report of a user games as JSON, or as CSV with format=csv.
+// query parameters are user, days, depth, format and group, only user is required.
+func (c ReportController) PlayerReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	user := query.Get("user")
+	if user == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(fmt.Errorf("user is required")))
+		return
+	}
+
+	if !userNamePattern.MatchString(user) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(fmt.Errorf("user can only have letters, digits, _ and -")))
+		return
+	}
+
+	days, err := intParam(query.Get("days"), defaultReportDays)
+	if err == nil && (days < 1 || days > maxReportDays) {
+		err = fmt.Errorf("days must be between 1 and %d", maxReportDays)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	depth, err := intParam(query.Get("depth"), 0)
+	if err == nil {
+		err = validateDepth(depth)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	format, group := query.Get("format"), query.Get("group")
+	err = validateReportFormat(format, group)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	resp, err := c.IReportService.PlayerReport(r.Context(), user, days, depth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	if format != "csv" {
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	buf := bytes.Buffer{}
+	err = c.IReportService.WriteCSV(&buf, resp, group)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", user+".csv"))
+	w.Write(buf.Bytes())
+}
+
+// returns an error if the report can not be written as format with its rows grouped by group,
+// so a wrong parameter is found before the games are analyzed
+func validateReportFormat(format, group string) error {
+	switch format {
+	case "", "json", "csv":
+	default:
+		return fmt.Errorf("format %q is not supported", format)
+	}
+
+	switch group {
+	case "", interfaces.CSVGroupGames, interfaces.CSVGroupTimeControl, interfaces.CSVGroupWeek:
+		return nil
+	default:
+		return fmt.Errorf("csv group %q is not supported", group)
+	}
+}
+
+// returns value as a number, or def if it is empty
+func intParam(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chenizz/internal/services/mocks"
+	"chenizz/internal/viewmodels"
+)
+
+func Test_PlayerReport_JSON(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+
+	serviceResponse := viewmodels.PlayerReportResponse{
+		Player: "alice",
+		Games:  []viewmodels.GameReportResponse{{Site: "g1", TimeControl: "180+0", ACPL: 30.5, Accuracy: 88}},
+		ByWeek: []viewmodels.AggregateResponse{{Key: "2024-W08", Games: 1, ACPL: 30.5, Accuracy: 88}},
+	}
+	serviceMock := mocks.ReportServiceMock{}
+	serviceMock.PatchPlayerReport(serviceResponse, nil)
+	controller := ReportController{serviceMock}
+
+	req := httptest.NewRequest("GET", "/game/chess/report?user=alice&days=7", nil)
+	rr := httptest.NewRecorder()
+
+	// Act
+	http.HandlerFunc(controller.PlayerReport).ServeHTTP(rr, req)
+	resp := viewmodels.PlayerReportResponse{}
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	// Assert
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(serviceResponse, resp)
+}
+
+func Test_PlayerReport_CSV(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	serviceMock := mocks.ReportServiceMock{}
+	serviceMock.PatchPlayerReport(viewmodels.PlayerReportResponse{Player: "alice"}, nil)
+	controller := ReportController{serviceMock}
+
+	req := httptest.NewRequest("GET", "/game/chess/report?user=alice&format=csv&group=week", nil)
+	rr := httptest.NewRecorder()
+
+	// Act
+	http.HandlerFunc(controller.PlayerReport).ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("text/csv", rr.Header().Get("Content-Type"))
+	assert.Equal(`attachment; filename="alice.csv"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal("alice,week\n", rr.Body.String())
+}
+
+func Test_PlayerReport_Errors(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	serviceMock := mocks.ReportServiceMock{}
+	serviceMock.PatchPlayerReport(viewmodels.PlayerReportResponse{}, fmt.Errorf("error in service"))
+	controller := ReportController{serviceMock}
+
+	testCases := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/game/chess/report", http.StatusBadRequest, "{\"error\":\"user is required\"}\n"},
+		{"/game/chess/report?user=al%2Fice%3Fx", http.StatusBadRequest, "{\"error\":\"user can only have letters, digits, _ and -\"}\n"},
+		{"/game/chess/report?user=alice&days=week", http.StatusBadRequest, "{\"error\":\"\\\"week\\\" is not a number\"}\n"},
+		{"/game/chess/report?user=alice&days=0", http.StatusBadRequest, "{\"error\":\"days must be between 1 and 90\"}\n"},
+		{"/game/chess/report?user=alice&days=365", http.StatusBadRequest, "{\"error\":\"days must be between 1 and 90\"}\n"},
+		{"/game/chess/report?user=alice&depth=60", http.StatusBadRequest, "{\"error\":\"depth must be between 0 and 30\"}\n"},
+		{"/game/chess/report?user=alice&format=xml", http.StatusBadRequest, "{\"error\":\"format \\\"xml\\\" is not supported\"}\n"},
+		{"/game/chess/report?user=alice&format=csv&group=month", http.StatusBadRequest, "{\"error\":\"csv group \\\"month\\\" is not supported\"}\n"},
+		{"/game/chess/report?user=alice", http.StatusUnprocessableEntity, "{\"error\":\"error in service\"}\n"},
+	}
+
+	for _, tc := range testCases {
+		rr := httptest.NewRecorder()
+
+		// Act
+		http.HandlerFunc(controller.PlayerReport).ServeHTTP(rr, httptest.NewRequest("GET", tc.url, nil))
+
+		// Assert
+		assert.Equal(tc.code, rr.Code, tc.url)
+		assert.Equal(tc.body, rr.Body.String(), tc.url)
+	}
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"chenizz/internal/viewmodels"
+)
+
+// MaxAnalysisDepth is the deepest search a request can ask for, every position of a game is searched until it
+const MaxAnalysisDepth = 30
+
+type IAnalysisService interface {
+	AnalyzeGame(ctx context.Context, pgn string, depth int) (viewmodels.GameAnalysisResponse, error)
+}
@@ -3,5 +3,5 @@ package interfaces
 import "chenizz/internal/viewmodels"
 
 type IChessGameService interface {
-	MakeMove(move string, fen string) (viewmodels.ChessGameResponse, error)
+	MakeMove(move string, fen string, variant string, history ...string) (viewmodels.ChessGameResponse, error)
 }
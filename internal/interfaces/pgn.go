@@ -0,0 +1,7 @@
+package interfaces
+
+import "chenizz/internal/viewmodels"
+
+type IPGNService interface {
+	ParsePGN(pgn string) (viewmodels.PGNResponse, error)
+}
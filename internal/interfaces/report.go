@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+
+	"chenizz/internal/viewmodels"
+)
+
+// groups of the rows of a report written as CSV
+const (
+	CSVGroupGames       = "games"
+	CSVGroupTimeControl = "time_control"
+	CSVGroupWeek        = "week"
+)
+
+type IReportService interface {
+	PlayerReport(ctx context.Context, user string, days, depth int) (viewmodels.PlayerReportResponse, error)
+	WriteCSV(w io.Writer, report viewmodels.PlayerReportResponse, group string) error
+}
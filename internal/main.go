@@ -1,18 +1,41 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
 
+	"chenizz/internal/services"
 	"github.com/gorilla/mux"
 )
 
 func main() {
+	uci := flag.Bool("uci", false, "run the engine with UCI protocol over stdin and stdout instead of the HTTP server")
+	flag.Parse()
+
+	if *uci {
+		if err := services.RunUCI(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "uci: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	chessGameController := ServiceContainer().ChessGameController()
+	analysisController := ServiceContainer().AnalysisController()
+	reportController := ServiceContainer().ReportController()
+	pgnController := ServiceContainer().PGNController()
 
 	r := mux.NewRouter()
 	r.HandleFunc("/game/chess/make-move", chessGameController.MakeMove)
+	r.HandleFunc("/game/chess/analyze", analysisController.AnalyzeGame)
+	r.HandleFunc("/game/chess/report", reportController.PlayerReport).Methods(http.MethodGet)
+	r.HandleFunc("/game/chess/pgn", pgnController.ParsePGN).Methods(http.MethodPost)
 	http.Handle("/game/chess/make-move", r)
+	http.Handle("/game/chess/analyze", r)
+	http.Handle("/game/chess/report", r)
+	http.Handle("/game/chess/pgn", r)
 
 	fmt.Printf("call ListenAndServe: %v", http.ListenAndServe(":8080", r))
 }
@@ -1,9 +1,18 @@
 package main
 
-import "chenizz/internal/controllers"
+import (
+	"os"
+	"strconv"
+
+	"chenizz/internal/controllers"
+	"chenizz/internal/services"
+)
 
 type IServiceContainer interface {
 	ChessGameController() controllers.ChessGameController
+	AnalysisController() controllers.AnalysisController
+	ReportController() controllers.ReportController
+	PGNController() controllers.PGNController
 }
 
 type k struct{}
@@ -12,6 +21,26 @@ func (k k) ChessGameController() controllers.ChessGameController {
 	return controllers.ChessGameController{}
 }
 
+// games are analyzed with the UCI engine of CHENIZZ_ENGINE_PATH, or the built-in one if it is not set
+func (k k) AnalysisController() controllers.AnalysisController {
+	return controllers.AnalysisController{
+		IAnalysisService: services.AnalysisService{EnginePath: os.Getenv("CHENIZZ_ENGINE_PATH")},
+	}
+}
+
+// reports analyze games from Lichess with the same engine as AnalysisController,
+// running CHENIZZ_ENGINES processes of it for each report, one if it is not set
+func (k k) ReportController() controllers.ReportController {
+	engines, _ := strconv.Atoi(os.Getenv("CHENIZZ_ENGINES"))
+	return controllers.ReportController{
+		IReportService: services.NewLichessReportService(os.Getenv("CHENIZZ_ENGINE_PATH"), engines),
+	}
+}
+
+func (k k) PGNController() controllers.PGNController {
+	return controllers.PGNController{IPGNService: services.PGNService{}}
+}
+
 func ServiceContainer() IServiceContainer {
 	return k{}
 }
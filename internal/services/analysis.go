@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"chenizz/internal/interfaces"
+	"chenizz/internal/services/internal/analysis"
+	"chenizz/internal/services/internal/enginepool"
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/viewmodels"
+)
+
+type AnalysisService struct {
+	// UCI engine that evaluates positions, the built-in search is used if it is empty
+	EnginePath string
+}
+
+const (
+	// default depths of the search of every position
+	builtinAnalysisDepth = 4
+	engineAnalysisDepth  = 16
+
+	// deepest search of every position, deeper ones take too long for a request
+	maxBuiltinAnalysisDepth = 6
+	maxEngineAnalysisDepth  = interfaces.MaxAnalysisDepth
+)
+
+// AnalyzeGame annotates every main line move of the first game of a plain-text PGN with its
+// centipawn loss and classification, and sums up ACPL and accuracy of each player.
+// positions are searched until depth, a default one is used if it is not positive
+// and it is limited to the deepest one the engine can search in a request.
+func (a AnalysisService) AnalyzeGame(ctx context.Context, pgnText string, depth int) (viewmodels.GameAnalysisResponse, error) {
+	game, err := pgn.NewReader(strings.NewReader(pgnText)).Read()
+	if err != nil && err != io.EOF {
+		return viewmodels.GameAnalysisResponse{}, fmt.Errorf("error calling reader.Read: %w", err)
+	}
+
+	if len(game.UCIFormatMoves) == 0 {
+		return viewmodels.GameAnalysisResponse{}, fmt.Errorf("pgn has no moves to analyze")
+	}
+
+	evaluator, closeEvaluator, err := gameEvaluator(ctx, a.EnginePath, 1, depth)
+	if err != nil {
+		return viewmodels.GameAnalysisResponse{}, err
+	}
+	defer closeEvaluator()
+
+	report, err := analysis.AnalyzeGame(ctx, evaluator, game)
+	if err != nil {
+		return viewmodels.GameAnalysisResponse{}, fmt.Errorf("error calling analysis.AnalyzeGame: %w", err)
+	}
+
+	return analysisResponse(report), nil
+}
+
+// returns the evaluator to analyze with and a function that releases it.
+// the built-in search is used if enginePath is empty, otherwise a pool of engines processes.
+func gameEvaluator(ctx context.Context, enginePath string, engines, depth int) (analysis.GameEvaluator, func(), error) {
+	if enginePath == "" {
+		depth = analysisDepth(depth, builtinAnalysisDepth, maxBuiltinAnalysisDepth)
+		return analysis.NewBuiltin(search.Limits{Depth: depth}), func() {}, nil
+	}
+
+	depth = analysisDepth(depth, engineAnalysisDepth, maxEngineAnalysisDepth)
+
+	pool, err := enginepool.New(ctx, enginepool.UCIStarter(enginePath), enginepool.Config{
+		Size:   engines,
+		Limits: search.Limits{Depth: depth},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error calling enginepool.New: %w", err)
+	}
+
+	return pool, func() { pool.Close() }, nil
+}
+
+// returns def if depth is not positive, and deepest if depth is deeper than it
+func analysisDepth(depth, def, deepest int) int {
+	switch {
+	case depth <= 0:
+		return def
+	case depth > deepest:
+		return deepest
+	}
+
+	return depth
+}
+
+func analysisResponse(report analysis.Report) viewmodels.GameAnalysisResponse {
+	response := viewmodels.GameAnalysisResponse{
+		White: playerResponse(report.White),
+		Black: playerResponse(report.Black),
+		Moves: make([]viewmodels.MoveAnalysisResponse, 0, len(report.Moves)),
+	}
+
+	for _, m := range report.Moves {
+		response.Moves = append(response.Moves, viewmodels.MoveAnalysisResponse{
+			Ply:            m.Ply,
+			Color:          m.Color,
+			Move:           m.Move,
+			SAN:            m.SAN,
+			BestMove:       m.BestMove,
+			EvalBefore:     evalResponse(m.EvalBefore),
+			EvalAfter:      evalResponse(m.EvalAfter),
+			CentipawnLoss:  m.CentipawnLoss,
+			WinChanceDrop:  m.WinChanceDrop,
+			Accuracy:       m.Accuracy,
+			Classification: string(m.Classification),
+		})
+	}
+
+	return response
+}
+
+func playerResponse(s analysis.PlayerSummary) viewmodels.PlayerAnalysisResponse {
+	classifications := make(map[string]int, len(s.Classifications))
+	for c, count := range s.Classifications {
+		classifications[string(c)] = count
+	}
+
+	return viewmodels.PlayerAnalysisResponse{
+		Name:            s.Name,
+		ACPL:            s.ACPL,
+		Accuracy:        s.Accuracy,
+		Classifications: classifications,
+	}
+}
+
+func evalResponse(e analysis.Eval) viewmodels.EvalResponse {
+	return viewmodels.EvalResponse{Centipawns: e.Centipawns, IsMate: e.IsMate, Mate: e.Mate}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AnalyzeGame_FoolsMate(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	game := "[White \"alice\"]\n[Black \"bob\"]\n\n1. f3 e5 2. g4 Qh4# 0-1"
+
+	a := AnalysisService{}
+
+	// Act
+	r, err := a.AnalyzeGame(context.Background(), game, 3)
+
+	// Assert
+	assert.Nil(err)
+	assert.Len(r.Moves, 4)
+	assert.Equal("g4", r.Moves[2].SAN)
+	assert.Equal("blunder", r.Moves[2].Classification)
+	assert.Equal("Qh4#", r.Moves[3].SAN)
+	assert.Equal("best", r.Moves[3].Classification)
+	assert.Equal("alice", r.White.Name)
+	assert.Equal(1, r.White.Classifications["blunder"])
+	assert.Greater(r.White.ACPL, r.Black.ACPL)
+}
+
+func Test_AnalyzeGame_LichessExport(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	game := `[White "alice"]
+[Black "bob"]
+
+1. f3 { [%clk 0:03:00] } 1... e5 { [%clk 0:03:00] }
+2. g4 (2. e4 Nf6) 2... Qh4# { [%clk 0:02:58] } 0-1`
+
+	a := AnalysisService{}
+
+	// Act
+	r, err := a.AnalyzeGame(context.Background(), game, 2)
+
+	// Assert
+	assert.Nil(err)
+	assert.Len(r.Moves, 4)
+	assert.Equal("Qh4#", r.Moves[3].SAN)
+}
+
+func Test_AnalyzeGame_IllegalMove(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	a := AnalysisService{}
+
+	// Act
+	_, err := a.AnalyzeGame(context.Background(), "1. e4 e4 *", 2)
+
+	// Assert
+	assert.EqualError(err, "error calling reader.Read: game 1, line 1, column 7: error calling board.ParseSAN: e4 is not a legal move")
+}
+
+func Test_AnalyzeGame_NoMoves(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	a := AnalysisService{}
+
+	// Act
+	_, err := a.AnalyzeGame(context.Background(), "[White \"alice\"]", 0)
+
+	// Assert
+	assert.EqualError(err, "pgn has no moves to analyze")
+}
+
+func Test_AnalyzeGame_EngineNotFound(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	a := AnalysisService{EnginePath: "./missing-engine"}
+
+	// Act
+	_, err := a.AnalyzeGame(context.Background(), "1. e4 e5 *", 0)
+
+	// Assert
+	assert.ErrorContains(err, "error calling enginepool.New")
+}
+
+func Test_analysisDepth(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(builtinAnalysisDepth, analysisDepth(0, builtinAnalysisDepth, maxBuiltinAnalysisDepth))
+	assert.Equal(3, analysisDepth(3, builtinAnalysisDepth, maxBuiltinAnalysisDepth))
+	assert.Equal(maxBuiltinAnalysisDepth, analysisDepth(60, builtinAnalysisDepth, maxBuiltinAnalysisDepth))
+	assert.Equal(maxEngineAnalysisDepth, analysisDepth(60, engineAnalysisDepth, maxEngineAnalysisDepth))
+}
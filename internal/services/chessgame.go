@@ -10,39 +10,67 @@ import (
 type ChessGameService struct {
 }
 
-func (c ChessGameService) MakeMove(move string, fen string) (viewmodels.ChessGameResponse, error) {
+const (
+	variantChess960     = "Chess960"
+	variantFromPosition = "From Position"
+)
+
+// MakeMove applies move to the position described by fen.
+// variant is the name of the rules game is played with as written in PGN Variant tag,
+// standard rules are used if it is empty.
+// history are FENs of positions reached before fen, oldest first, used to detect repetitions.
+func (c ChessGameService) MakeMove(move string, fen string, variant string, history ...string) (viewmodels.ChessGameResponse, error) {
 	b := chess.NewBoard()
+	rules, ok := chess.VariantByName(variant)
+	if !ok && variant != "" && variant != variantChess960 && variant != variantFromPosition {
+		return viewmodels.ChessGameResponse{}, fmt.Errorf("variant %q is not supported", variant)
+	}
+
+	if ok {
+		b.SetVariant(rules)
+	}
+
+	if variant == variantChess960 {
+		b.SetChess960(true)
+	}
+
 	err := b.TranslateFEN(fen)
 	if err != nil {
 		return viewmodels.ChessGameResponse{}, fmt.Errorf("error calling b.TranslateFEN: %w", err)
 	}
 
+	err = b.SetPreviousPositions(history...)
+	if err != nil {
+		return viewmodels.ChessGameResponse{}, fmt.Errorf("error calling b.SetPreviousPositions: %w", err)
+	}
+
 	availableMoves := b.AvailableLegalMoves()
-	if !contains(availableMoves, move) {
+	m, err := b.ParseMove(move)
+	if err != nil {
 		return viewmodels.ChessGameResponse{AvailableMoves: availableMoves},
 			fmt.Errorf("%s is not a legal move", move)
 	}
 
-	b.MakeMove(move)
+	san := b.SAN(m)
+	b.Play(m)
 	availableMoves = b.AvailableLegalMoves()
 
+	outcome := b.Outcome()
 	response := viewmodels.ChessGameResponse{
-		MoveDone:       true,
-		AvailableMoves: availableMoves,
-		FEN:            b.FEN(),
+		MoveDone:               true,
+		SAN:                    san,
+		IsThreefoldRepetition:  b.IsThreefoldRepetition(),
+		IsFivefoldRepetition:   b.IsFivefoldRepetition(),
+		IsFiftyMoveRule:        b.IsFiftyMoveRule(),
+		IsSeventyFiveMoveRule:  b.IsSeventyFiveMoveRule(),
+		IsInsufficientMaterial: b.IsInsufficientMaterial(),
+		Outcome:                outcome.Result,
+		Termination:            string(outcome.Termination),
+		AvailableMoves:         availableMoves,
+		FEN:                    b.FEN(),
 	}
 
-	response.IsCheckMate = availableMoves == nil
-	response.IsStaleMate = availableMoves != nil && len(availableMoves) == 0
+	response.IsCheckMate = outcome.Termination == chess.Checkmate
+	response.IsStaleMate = outcome.Termination == chess.Stalemate
 	return response, nil
 }
-
-func contains(s []string, m string) bool {
-	for _, v := range s {
-		if v == m {
-			return true
-		}
-	}
-
-	return false
-}
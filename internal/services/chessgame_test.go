@@ -17,11 +17,12 @@ func Test_MakeMove_MoveIsValid(t *testing.T) {
 	c := ChessGameService{}
 
 	// Act
-	r, err := c.MakeMove(providedMove, providedFen)
+	r, err := c.MakeMove(providedMove, providedFen, "")
 
 	// Assert
 	assert.Nil(err)
 	assert.True(r.MoveDone)
+	assert.Equal("d4", r.SAN)
 	assert.False(r.IsCheckMate)
 	assert.False(r.IsStaleMate)
 	assert.ElementsMatch(availableMovesForBlack, r.AvailableMoves)
@@ -38,7 +39,7 @@ func Test_MakeMove_MoveIsNotValid(t *testing.T) {
 	c := ChessGameService{}
 
 	// Act
-	r, err := c.MakeMove(providedMove, providedFen)
+	r, err := c.MakeMove(providedMove, providedFen, "")
 
 	// Assert
 	assert.EqualError(err, "a2a3 is not a legal move")
@@ -57,16 +58,33 @@ func Test_MakeMove_InvalidFEN(t *testing.T) {
 	c := ChessGameService{}
 
 	// Act
-	r, err := c.MakeMove(providedMove, providedFen)
+	r, err := c.MakeMove(providedMove, providedFen, "")
 
 	// Assert
-	assert.EqualError(err, "error calling b.TranslateFEN: invalid FEN")
+	assert.EqualError(err, "error calling b.TranslateFEN: invalid FEN fields: expected 4 to 6 fields, got 1")
 	assert.False(r.MoveDone)
 	assert.False(r.IsCheckMate)
 	assert.False(r.IsStaleMate)
 	assert.Nil(r.AvailableMoves)
 }
 
+func Test_MakeMove_IllegalPosition(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "8/8/8/8/8/8/3P4/8 w - - 0 1"
+	providedMove := "d2d4"
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "")
+
+	// Assert
+	assert.EqualError(err, "error calling b.TranslateFEN: invalid FEN piece_placement: there must be one white king, got 0")
+	assert.False(r.MoveDone)
+	assert.Nil(r.AvailableMoves)
+}
+
 func Test_MakeMove_MoveIsCheckMate(t *testing.T) {
 	// Arrange
 	assert := assert.New(t)
@@ -76,13 +94,16 @@ func Test_MakeMove_MoveIsCheckMate(t *testing.T) {
 	c := ChessGameService{}
 
 	// Act
-	r, err := c.MakeMove(providedMove, providedFen)
+	r, err := c.MakeMove(providedMove, providedFen, "")
 
 	// Assert
 	assert.Nil(err)
 	assert.True(r.MoveDone)
+	assert.Equal("Nxb2#", r.SAN)
 	assert.True(r.IsCheckMate)
 	assert.False(r.IsStaleMate)
+	assert.Equal("1-0", r.Outcome)
+	assert.Equal("checkmate", r.Termination)
 	assert.Nil(r.AvailableMoves)
 }
 
@@ -95,7 +116,7 @@ func Test_MakeMove_MoveIsStaleMate(t *testing.T) {
 	c := ChessGameService{}
 
 	// Act
-	r, err := c.MakeMove(providedMove, providedFen)
+	r, err := c.MakeMove(providedMove, providedFen, "")
 
 	// Assert
 	assert.Nil(err)
@@ -104,3 +125,122 @@ func Test_MakeMove_MoveIsStaleMate(t *testing.T) {
 	assert.True(r.IsStaleMate)
 	assert.Empty(r.AvailableMoves)
 }
+
+func Test_MakeMove_ThreefoldRepetition(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "r1bqkbnr/pppppppp/2n5/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 7 4"
+	providedMove := "c6b8"
+	history := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R b KQkq - 1 1",
+		"r1bqkbnr/pppppppp/2n5/8/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 2 2",
+		"r1bqkbnr/pppppppp/2n5/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 3 2",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 4 3",
+		"rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R b KQkq - 5 3",
+		"r1bqkbnr/pppppppp/2n5/8/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 6 4",
+	}
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "", history...)
+
+	// Assert
+	assert.Nil(err)
+	assert.True(r.MoveDone)
+	assert.True(r.IsThreefoldRepetition)
+	assert.False(r.IsFivefoldRepetition)
+	assert.Equal("1/2-1/2", r.Outcome)
+	assert.Equal("threefold_repetition", r.Termination)
+}
+
+func Test_MakeMove_InsufficientMaterial(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "8/8/4k3/8/3r4/3K4/8/8 w - - 0 1"
+	providedMove := "d3d4"
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "")
+
+	// Assert
+	assert.Nil(err)
+	assert.True(r.MoveDone)
+	assert.True(r.IsInsufficientMaterial)
+	assert.Equal("1/2-1/2", r.Outcome)
+	assert.Equal("insufficient_material", r.Termination)
+	assert.NotEmpty(r.AvailableMoves)
+}
+
+func Test_MakeMove_InvalidHistory(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "8/5pkp/6p1/8/8/6K1/3P1PPP/8 w - - 0 1"
+	providedMove := "d2d4"
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "", "invalid-fen")
+
+	// Assert
+	assert.EqualError(err, "error calling b.SetPreviousPositions: error calling board.parsePosition: invalid FEN fields: expected 4 to 6 fields, got 1")
+	assert.False(r.MoveDone)
+}
+
+func Test_MakeMove_AntichessHistoryWithoutKings(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "4k3/8/8/8/8/8/8/R7 w - - 1 11"
+	providedMove := "a1a2"
+	history := []string{"3k4/8/8/8/8/8/8/R7 b - - 0 10"}
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "Antichess", history...)
+
+	// Assert
+	assert.Nil(err)
+	assert.True(r.MoveDone)
+}
+
+func Test_MakeMove_KingOfTheHill(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "rnbq1bnr/pppp1ppp/4k3/4P3/8/3K4/PPP2PPP/RNBQ1BNR b - - 1 4"
+	providedMove := "e6e5"
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "King of the Hill")
+
+	// Assert
+	assert.Nil(err)
+	assert.True(r.MoveDone)
+	assert.False(r.IsCheckMate)
+	assert.False(r.IsStaleMate)
+	assert.Equal("0-1", r.Outcome)
+	assert.Equal("king_in_the_center", r.Termination)
+	assert.Empty(r.AvailableMoves)
+}
+
+func Test_MakeMove_UnsupportedVariant(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	providedFen := "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	providedMove := "e2e4"
+
+	c := ChessGameService{}
+
+	// Act
+	r, err := c.MakeMove(providedMove, providedFen, "Horde")
+
+	// Assert
+	assert.EqualError(err, `variant "Horde" is not supported`)
+	assert.False(r.MoveDone)
+}
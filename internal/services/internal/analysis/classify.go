@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"math"
+
+	"chenizz/internal/services/internal/search"
+)
+
+// Classification of a move by how much it changes the winning chances of its player
+type Classification string
+
+const (
+	// best move that gives up material
+	Brilliant Classification = "brilliant"
+
+	// engine best move
+	Best Classification = "best"
+
+	Excellent  Classification = "excellent"
+	Good       Classification = "good"
+	Inaccuracy Classification = "inaccuracy"
+	Mistake    Classification = "mistake"
+	Blunder    Classification = "blunder"
+
+	// move that lets a forced mate go
+	MissedMate Classification = "missed_mate"
+)
+
+// Classifications in order from the best kind of move to the worst one
+var Classifications = []Classification{Brilliant, Best, Excellent, Good, Inaccuracy, Mistake, Blunder, MissedMate}
+
+const (
+	// value of a mate in centipawns, evaluations are capped to it
+	mateCentipawns = 1000
+
+	// drops of winning chances, from 0 to 100, each one is the exclusive upper bound
+	// of the classification it is named after, drops from mistakeDrop on are blunders
+	excellentDrop  = 2
+	goodDrop       = 5
+	inaccuracyDrop = 10
+	mistakeDrop    = 15
+
+	// winning chances a player must keep after a sacrifice to be brilliant,
+	// and the ones it can not have before, since sacrifices are easy when winning
+	brilliantMinWin = 50
+	brilliantMaxWin = 90
+)
+
+// a move judged by the evaluations of the positions before and after it,
+// both from the point of view of the player who makes it, so mate 0 is a mate given by it
+type judgement struct {
+	played, best  string
+	before, after search.Score
+
+	// true if the move gives up material
+	sacrifice bool
+}
+
+// centipawns of s capped to mate value, s is from the point of view of the player who moves
+func centipawns(s search.Score) int {
+	if s.IsMate {
+		if s.Mate >= 0 {
+			return mateCentipawns
+		}
+
+		return -mateCentipawns
+	}
+
+	if s.Centipawns > mateCentipawns {
+		return mateCentipawns
+	}
+
+	if s.Centipawns < -mateCentipawns {
+		return -mateCentipawns
+	}
+
+	return s.Centipawns
+}
+
+// winPercent returns the chances of winning from 0 to 100 of a player with cp advantage,
+// with the formula Lichess fitted to its games
+func winPercent(cp int) float64 {
+	return 50 + 50*(2/(1+math.Exp(-0.00368208*float64(cp)))-1)
+}
+
+// moveAccuracy returns how accurate a move is from 0 to 100 given winning chances
+// before and after it, with the formula Lichess uses
+func moveAccuracy(winBefore, winAfter float64) float64 {
+	if winAfter >= winBefore {
+		return 100
+	}
+
+	accuracy := 103.1668*math.Exp(-0.04354*(winBefore-winAfter)) - 3.1669
+	return math.Max(0, math.Min(100, accuracy))
+}
+
+func (j judgement) centipawnLoss() int {
+	loss := centipawns(j.before) - centipawns(j.after)
+	if loss < 0 {
+		return 0
+	}
+
+	return loss
+}
+
+func (j judgement) winChanceDrop() float64 {
+	return math.Max(0, winPercent(centipawns(j.before))-winPercent(centipawns(j.after)))
+}
+
+// a mate is missed if there was a forced one before the move and there is not anymore
+func (j judgement) missesMate() bool {
+	hadMate := j.before.IsMate && j.before.Mate > 0
+	keepsMate := j.after.IsMate && j.after.Mate >= 0
+	return hadMate && !keepsMate
+}
+
+func (j judgement) classify() Classification {
+	if j.played == j.best {
+		win := winPercent(centipawns(j.after))
+		if j.sacrifice && win >= brilliantMinWin && winPercent(centipawns(j.before)) < brilliantMaxWin {
+			return Brilliant
+		}
+
+		return Best
+	}
+
+	if j.missesMate() {
+		return MissedMate
+	}
+
+	switch drop := j.winChanceDrop(); {
+	case drop < excellentDrop:
+		return Excellent
+	case drop < goodDrop:
+		return Good
+	case drop < inaccuracyDrop:
+		return Inaccuracy
+	case drop < mistakeDrop:
+		return Mistake
+	}
+
+	return Blunder
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"testing"
+
+	"chenizz/internal/services/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_winPercent(t *testing.T) {
+	assert.Equal(t, 50.0, winPercent(0))
+	assert.InDelta(t, 59.1, winPercent(100), 0.1)
+	assert.InDelta(t, 40.9, winPercent(-100), 0.1)
+	assert.InDelta(t, 97.5, winPercent(1000), 0.1)
+}
+
+func Test_moveAccuracy(t *testing.T) {
+	assert.Equal(t, 100.0, moveAccuracy(50, 60))
+	assert.InDelta(t, 100.0, moveAccuracy(50, 50), 0.01)
+	assert.InDelta(t, 63.6, moveAccuracy(60, 50), 0.1)
+	assert.Equal(t, 0.0, moveAccuracy(100, 0))
+}
+
+func Test_judgement(t *testing.T) {
+	cp := func(n int) search.Score { return search.Score{Centipawns: n} }
+	mate := func(n int) search.Score { return search.Score{IsMate: true, Mate: n} }
+
+	testCases := []struct {
+		name     string
+		j        judgement
+		expected Classification
+		loss     int
+	}{
+		{"best move", judgement{played: "e2e4", best: "e2e4", before: cp(30), after: cp(25)}, Best, 5},
+		{"sacrifice", judgement{played: "d1h5", best: "d1h5", before: cp(30), after: cp(200), sacrifice: true}, Brilliant, 0},
+		{"sacrifice when losing", judgement{played: "d1h5", best: "d1h5", before: cp(-300), after: cp(-300), sacrifice: true}, Best, 0},
+		{"sacrifice when winning", judgement{played: "d1h5", best: "d1h5", before: cp(900), after: cp(900), sacrifice: true}, Best, 0},
+		{"excellent", judgement{played: "d2d4", best: "e2e4", before: cp(30), after: cp(25)}, Excellent, 5},
+		{"good", judgement{played: "d2d4", best: "e2e4", before: cp(30), after: cp(0)}, Good, 30},
+		{"inaccuracy", judgement{played: "d2d4", best: "e2e4", before: cp(30), after: cp(-40)}, Inaccuracy, 70},
+		{"mistake", judgement{played: "d2d4", best: "e2e4", before: cp(30), after: cp(-100)}, Mistake, 130},
+		{"blunder", judgement{played: "d2d4", best: "e2e4", before: cp(30), after: cp(-300)}, Blunder, 330},
+		{"allows mate", judgement{played: "g2g4", best: "e2e4", before: cp(0), after: mate(-1)}, Blunder, 1000},
+		{"missed mate", judgement{played: "d2d4", best: "h5f7", before: mate(1), after: cp(500)}, MissedMate, 500},
+		{"slower mate", judgement{played: "d2d4", best: "h5f7", before: mate(1), after: mate(3)}, Excellent, 0},
+		{"mate given", judgement{played: "h5f7", best: "h5f7", before: mate(1), after: mate(0)}, Best, 0},
+		{"won position", judgement{played: "a2a3", best: "h5f7", before: cp(2000), after: cp(1500)}, Excellent, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.j.classify())
+			assert.Equal(t, tc.loss, tc.j.centipawnLoss())
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"chenizz/internal/services/internal/chess"
+	"chenizz/internal/services/internal/enginepool"
+	"chenizz/internal/services/internal/evaluation"
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/services/internal/uci"
+)
+
+type (
+	// GameEvaluator analyzes every position of games, like *enginepool.Pool
+	GameEvaluator interface {
+		Analyze(ctx context.Context, games []enginepool.Game, onProgress func(enginepool.Progress)) []enginepool.GameResult
+	}
+
+	// Builtin is a GameEvaluator that analyzes positions with the built-in search
+	Builtin struct {
+		engine *search.Engine
+		limits search.Limits
+	}
+)
+
+// variants UCI engines analyze without any option
+var standardVariants = map[string]bool{"": true, "Standard": true, "From Position": true}
+
+// NewBuiltin returns a GameEvaluator that searches every position until limits
+func NewBuiltin(limits search.Limits) *Builtin {
+	return &Builtin{
+		engine: search.NewEngine(evaluation.NewEvaluator(evaluation.DefaultWeights()), 0),
+		limits: limits,
+	}
+}
+
+func (b *Builtin) Analyze(ctx context.Context, games []enginepool.Game, onProgress func(enginepool.Progress)) []enginepool.GameResult {
+	results := make([]enginepool.GameResult, 0, len(games))
+	for _, g := range games {
+		results = append(results, b.analyzeGame(ctx, g, onProgress))
+	}
+
+	return results
+}
+
+func (b *Builtin) analyzeGame(ctx context.Context, g enginepool.Game, onProgress func(enginepool.Progress)) enginepool.GameResult {
+	result := enginepool.GameResult{ID: g.ID, Positions: make([]uci.Analysis, 0, len(g.Moves)+1)}
+	board := chess.NewBoard()
+	if g.FEN != "" {
+		if err := board.TranslateFEN(g.FEN); err != nil {
+			result.Err = fmt.Errorf("error calling board.TranslateFEN: %w", err)
+			return result
+		}
+	}
+
+	b.engine.NewGame()
+	for ply := 0; ply <= len(g.Moves); ply++ {
+		if ply > 0 {
+			if _, err := board.ParseMove(g.Moves[ply-1]); err != nil {
+				result.Err = fmt.Errorf("error calling board.ParseMove: %w", err)
+				return result
+			}
+			board.MakeMove(g.Moves[ply-1])
+		}
+
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+			return result
+		}
+
+		r := b.engine.Search(ctx, board, b.limits)
+		analysis := uci.Analysis{}
+		if r.Depth > 0 {
+			pv := make([]string, 0, len(r.PV))
+			for _, m := range r.PV {
+				pv = append(pv, uci.FormatMove(m))
+			}
+
+			analysis.BestMove = uci.FormatMove(r.BestMove)
+			analysis.Lines = []uci.Info{{Depth: r.Depth, MultiPV: 1, Score: r.Score, Nodes: r.Nodes, Time: r.Time, PV: pv}}
+		}
+
+		result.Positions = append(result.Positions, analysis)
+		if onProgress != nil {
+			onProgress(enginepool.Progress{GameID: g.ID, Analyzed: ply + 1, Total: len(g.Moves) + 1})
+		}
+	}
+
+	return result
+}
+
+// Evaluate analyzes every position of game with evaluator, the result has
+// one more position than game moves. only games with standard rules can be evaluated.
+func Evaluate(ctx context.Context, evaluator GameEvaluator, game pgn.PGN) ([]uci.Analysis, error) {
+	g, err := engineGame(game, game.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	results := evaluator.Analyze(ctx, []enginepool.Game{g}, nil)
+	if len(results) != 1 {
+		return nil, fmt.Errorf("evaluator returned %d games instead of 1", len(results))
+	}
+
+	return results[0].Positions, results[0].Err
+}
+
+// returns game as engines take it, with moves in UCI notation
+func engineGame(game pgn.PGN, id string) (enginepool.Game, error) {
+	if !standardVariants[game.Variant] {
+		return enginepool.Game{}, fmt.Errorf("variant %q can not be analyzed", game.Variant)
+	}
+
+	board, err := game.StartingBoard()
+	if err != nil {
+		return enginepool.Game{}, err
+	}
+
+	g := enginepool.Game{ID: id, Moves: make([]string, 0, len(game.UCIFormatMoves))}
+	if game.FEN != "" {
+		g.FEN = board.FEN()
+	}
+
+	for _, move := range game.UCIFormatMoves {
+		m, err := board.ParseMove(move)
+		if err != nil {
+			return enginepool.Game{}, fmt.Errorf("error calling board.ParseMove: %w", err)
+		}
+
+		g.Moves = append(g.Moves, uci.FormatMove(m))
+		board.Play(m)
+	}
+
+	return g, nil
+}
+
+// AnalyzeGame evaluates game positions with evaluator and annotates its moves
+func AnalyzeGame(ctx context.Context, evaluator GameEvaluator, game pgn.PGN) (Report, error) {
+	positions, err := Evaluate(ctx, evaluator, game)
+	if err != nil {
+		return Report{}, fmt.Errorf("error calling Evaluate: %w", err)
+	}
+
+	return Annotate(game, positions)
+}
+
+// AnalyzeGames evaluates positions of every game with a single call to evaluator, so
+// an engine pool analyzes them at the same time, and annotates their moves.
+// reports and errors are in the order of games, a game that fails has an error and an empty report.
+// onProgress is called every time a position is analyzed, it can be nil.
+func AnalyzeGames(ctx context.Context, evaluator GameEvaluator, games []pgn.PGN, onProgress func(enginepool.Progress)) ([]Report, []error) {
+	reports, errs := make([]Report, len(games)), make([]error, len(games))
+
+	engineGames, indexes := []enginepool.Game{}, []int{}
+	for i, game := range games {
+		g, err := engineGame(game, strconv.Itoa(i))
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		engineGames = append(engineGames, g)
+		indexes = append(indexes, i)
+	}
+
+	results := evaluator.Analyze(ctx, engineGames, onProgress)
+	for j, result := range results {
+		i := indexes[j]
+		if result.Err != nil {
+			errs[i] = result.Err
+			continue
+		}
+
+		reports[i], errs[i] = Annotate(games[i], result.Positions)
+	}
+
+	return reports, errs
+}
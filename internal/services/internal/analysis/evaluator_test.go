@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"chenizz/internal/services/internal/enginepool"
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/services/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ GameEvaluator = &enginepool.Pool{}
+
+func Test_Builtin(t *testing.T) {
+	builtin := NewBuiltin(search.Limits{Depth: 2})
+
+	progress := []int{}
+	results := builtin.Analyze(context.Background(), []enginepool.Game{
+		{ID: "a", Moves: []string{"f2f3", "e7e5", "g2g4", "d8h4"}},
+	}, func(p enginepool.Progress) {
+		progress = append(progress, p.Analyzed)
+	})
+
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, progress)
+
+	positions := results[0].Positions
+	assert.Len(t, positions, 5)
+	assert.Equal(t, "d8h4", positions[3].BestMove)
+	assert.Equal(t, search.Score{IsMate: true, Mate: 1}, positions[3].Lines[0].Score)
+
+	// checkmate
+	assert.Equal(t, "", positions[4].BestMove)
+	assert.Empty(t, positions[4].Lines)
+
+	results = builtin.Analyze(context.Background(), []enginepool.Game{{ID: "b", Moves: []string{"e2e5"}}}, nil)
+	assert.ErrorContains(t, results[0].Err, "e2e5 is not a legal move")
+}
+
+func Test_AnalyzeGame(t *testing.T) {
+	games, err := pgn.ParseStringGames("[White \"alice\"]\n[Black \"bob\"]\n\n1. f3 e5 2. g4 Qh4# 0-1")
+	assert.Nil(t, err)
+
+	report, err := AnalyzeGame(context.Background(), NewBuiltin(search.Limits{Depth: 3}), games[0])
+	assert.Nil(t, err)
+
+	assert.Len(t, report.Moves, 4)
+	assert.Equal(t, Blunder, report.Moves[2].Classification)
+	assert.Equal(t, Best, report.Moves[3].Classification)
+	assert.Equal(t, 1, report.White.Classifications[Blunder])
+	assert.Equal(t, "bob", report.Black.Name)
+}
+
+func Test_Evaluate_Variant(t *testing.T) {
+	_, err := Evaluate(context.Background(), NewBuiltin(search.Limits{Depth: 1}), pgn.PGN{Variant: "Atomic"})
+	assert.EqualError(t, err, `variant "Atomic" can not be analyzed`)
+}
+
+func Test_AnalyzeGames(t *testing.T) {
+	games := []pgn.PGN{
+		{Variant: "Atomic", UCIFormatMoves: []string{"e2e4"}},
+		{White: "alice", UCIFormatMoves: []string{"f2f3", "e7e5", "g2g4", "d8h4"}},
+	}
+
+	calls := 0
+	reports, errs := AnalyzeGames(context.Background(), NewBuiltin(search.Limits{Depth: 2}), games, func(enginepool.Progress) {
+		calls++
+	})
+
+	assert.EqualError(t, errs[0], `variant "Atomic" can not be analyzed`)
+	assert.Nil(t, errs[1])
+	assert.Len(t, reports[1].Moves, 4)
+	assert.Equal(t, "alice", reports[1].White.Name)
+	assert.Equal(t, 5, calls)
+}
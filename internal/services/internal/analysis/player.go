@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"chenizz/internal/services/internal/pgn"
+)
+
+type (
+	// GameSummary is how a player played an analyzed game
+	GameSummary struct {
+		Site        string
+		Date        string
+		Week        string
+		TimeControl string
+
+		// color the player had, "w" or "b"
+		Color    string
+		Opponent string
+		Result   string
+
+		// moves made by the player
+		Moves    int
+		ACPL     float64
+		Accuracy float64
+	}
+
+	// Aggregate is the average ACPL and accuracy of the games of a group,
+	// every game weighs the same
+	Aggregate struct {
+		Key      string
+		Games    int
+		ACPL     float64
+		Accuracy float64
+	}
+
+	// PlayerReport shows how the ACPL and accuracy of a player change
+	// from a game to another, by time control and by week
+	PlayerReport struct {
+		Player        string
+		Games         []GameSummary
+		ByTimeControl []Aggregate
+		ByWeek        []Aggregate
+	}
+
+	// PlayerReportBuilder builds a PlayerReport from games added one at a time,
+	// so games do not need to be kept after they are added
+	PlayerReportBuilder struct {
+		player string
+		games  []orderedSummary
+	}
+
+	// summary of the game read in order
+	orderedSummary struct {
+		order int
+		GameSummary
+	}
+)
+
+// key of games without a known date or time control
+const unknownKey = "unknown"
+
+// BuildPlayerReport sums up the reports of the games player played, reports[i] is the one of games[i].
+// games without moves of player are left out. games are sorted by date, and weeks from the oldest one.
+func BuildPlayerReport(player string, games []pgn.PGN, reports []Report) PlayerReport {
+	b := NewPlayerReportBuilder(player)
+	for i, game := range games {
+		b.Add(i, game, reports[i])
+	}
+
+	return b.Build()
+}
+
+// NewPlayerReportBuilder returns a builder of the report of player
+func NewPlayerReportBuilder(player string) *PlayerReportBuilder {
+	return &PlayerReportBuilder{player: player}
+}
+
+// Add sums up the report of game, which is the ith game read. games of the same date keep that order,
+// so they can be added in any order. games without moves of player are left out.
+func (b *PlayerReportBuilder) Add(i int, game pgn.PGN, report Report) {
+	summary, ok := gameSummary(b.player, game, report)
+	if ok {
+		b.games = append(b.games, orderedSummary{i, summary})
+	}
+}
+
+// Build returns the report of the games added, sorted by date, and weeks from the oldest one
+func (b *PlayerReportBuilder) Build() PlayerReport {
+	sort.Slice(b.games, func(i, j int) bool {
+		if b.games[i].Date != b.games[j].Date {
+			return b.games[i].Date < b.games[j].Date
+		}
+
+		return b.games[i].order < b.games[j].order
+	})
+
+	r := PlayerReport{Player: b.player, Games: make([]GameSummary, 0, len(b.games))}
+	for _, g := range b.games {
+		r.Games = append(r.Games, g.GameSummary)
+	}
+
+	r.ByTimeControl = aggregate(r.Games, func(g GameSummary) string { return g.TimeControl })
+	r.ByWeek = aggregate(r.Games, func(g GameSummary) string { return g.Week })
+	return r
+}
+
+func gameSummary(player string, game pgn.PGN, report Report) (GameSummary, bool) {
+	summary := GameSummary{
+		Site:        game.Site,
+		Date:        game.Date,
+		Week:        isoWeek(game.Date),
+		TimeControl: game.TimeControl,
+		Result:      game.Result,
+	}
+
+	if summary.TimeControl == "" {
+		summary.TimeControl = unknownKey
+	}
+
+	var s PlayerSummary
+	switch {
+	case strings.EqualFold(game.White, player):
+		summary.Color, summary.Opponent, s = "w", game.Black, report.White
+	case strings.EqualFold(game.Black, player):
+		summary.Color, summary.Opponent, s = "b", game.White, report.Black
+	default:
+		return GameSummary{}, false
+	}
+
+	for _, count := range s.Classifications {
+		summary.Moves += count
+	}
+
+	if summary.Moves == 0 {
+		return GameSummary{}, false
+	}
+
+	summary.ACPL, summary.Accuracy = s.ACPL, s.Accuracy
+	return summary, true
+}
+
+// groups games by key in the order keys are found
+func aggregate(games []GameSummary, key func(GameSummary) string) []Aggregate {
+	aggregates := []Aggregate{}
+	indexes := map[string]int{}
+	for _, g := range games {
+		k := key(g)
+		i, ok := indexes[k]
+		if !ok {
+			i = len(aggregates)
+			indexes[k] = i
+			aggregates = append(aggregates, Aggregate{Key: k})
+		}
+
+		a := &aggregates[i]
+		a.Games++
+		a.ACPL += g.ACPL
+		a.Accuracy += g.Accuracy
+	}
+
+	for i := range aggregates {
+		a := &aggregates[i]
+		a.ACPL = round(a.ACPL / float64(a.Games))
+		a.Accuracy = round(a.Accuracy / float64(a.Games))
+	}
+
+	return aggregates
+}
+
+// returns ISO week of a PGN date like "2024.02.15" as "2024-W07"
+func isoWeek(date string) string {
+	t, err := time.Parse("2006.01.02", date)
+	if err != nil {
+		return unknownKey
+	}
+
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
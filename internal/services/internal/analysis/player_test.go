@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"testing"
+
+	"chenizz/internal/services/internal/pgn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildPlayerReport(t *testing.T) {
+	games := []pgn.PGN{
+		{Site: "g1", White: "Alice", Black: "bob", Date: "2024.02.20", TimeControl: "180+0", Result: "1-0"},
+		{Site: "g2", White: "carol", Black: "alice", Date: "2024.02.12", TimeControl: "600+0", Result: "0-1"},
+		{Site: "g3", White: "alice", Black: "dave", Date: "2024.02.13", TimeControl: "180+0", Result: "1/2-1/2"},
+		{Site: "g4", White: "bob", Black: "carol", Date: "2024.02.14", TimeControl: "180+0"},
+		{Site: "g5", White: "alice", Black: "bob", Date: "????.??.??"},
+	}
+
+	player := func(acpl, accuracy float64, moves int) PlayerSummary {
+		return PlayerSummary{ACPL: acpl, Accuracy: accuracy, Classifications: map[Classification]int{Best: moves}}
+	}
+
+	reports := []Report{
+		{White: player(20, 90, 30), Black: player(50, 70, 30)},
+		{White: player(80, 60, 25), Black: player(40, 80, 24)},
+		{White: player(30, 85, 40), Black: player(35, 82, 40)},
+		{White: player(10, 95, 10), Black: player(10, 95, 10)},
+		{White: player(0, 0, 0)},
+	}
+
+	r := BuildPlayerReport("alice", games, reports)
+
+	assert.Equal(t, "alice", r.Player)
+	assert.Equal(t, []GameSummary{
+		{Site: "g2", Date: "2024.02.12", Week: "2024-W07", TimeControl: "600+0", Color: "b", Opponent: "carol", Result: "0-1", Moves: 24, ACPL: 40, Accuracy: 80},
+		{Site: "g3", Date: "2024.02.13", Week: "2024-W07", TimeControl: "180+0", Color: "w", Opponent: "dave", Result: "1/2-1/2", Moves: 40, ACPL: 30, Accuracy: 85},
+		{Site: "g1", Date: "2024.02.20", Week: "2024-W08", TimeControl: "180+0", Color: "w", Opponent: "bob", Result: "1-0", Moves: 30, ACPL: 20, Accuracy: 90},
+	}, r.Games)
+
+	assert.Equal(t, []Aggregate{
+		{Key: "600+0", Games: 1, ACPL: 40, Accuracy: 80},
+		{Key: "180+0", Games: 2, ACPL: 25, Accuracy: 87.5},
+	}, r.ByTimeControl)
+
+	assert.Equal(t, []Aggregate{
+		{Key: "2024-W07", Games: 2, ACPL: 35, Accuracy: 82.5},
+		{Key: "2024-W08", Games: 1, ACPL: 20, Accuracy: 90},
+	}, r.ByWeek)
+}
+
+func Test_PlayerReportBuilder_AddInAnyOrder(t *testing.T) {
+	report := Report{White: PlayerSummary{ACPL: 10, Accuracy: 90, Classifications: map[Classification]int{Best: 5}}}
+	b := NewPlayerReportBuilder("alice")
+
+	b.Add(2, pgn.PGN{Site: "g3", White: "alice", Date: "2024.02.12"}, report)
+	b.Add(0, pgn.PGN{Site: "g1", White: "alice", Date: "2024.02.13"}, report)
+	b.Add(1, pgn.PGN{Site: "g2", White: "alice", Date: "2024.02.12"}, report)
+	r := b.Build()
+
+	sites := []string{}
+	for _, g := range r.Games {
+		sites = append(sites, g.Site)
+	}
+	assert.Equal(t, []string{"g2", "g3", "g1"}, sites)
+}
+
+func Test_isoWeek(t *testing.T) {
+	assert.Equal(t, "2024-W01", isoWeek("2024.01.01"))
+	assert.Equal(t, "2020-W53", isoWeek("2021.01.03"))
+	assert.Equal(t, "unknown", isoWeek("2024.??.??"))
+}
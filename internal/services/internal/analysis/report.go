@@ -0,0 +1,199 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"chenizz/internal/services/internal/chess"
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/services/internal/uci"
+)
+
+type (
+	// Eval of a position from white point of view
+	Eval struct {
+		Centipawns int
+		IsMate     bool
+
+		// moves until mate, negative if black mates. zero if game ended in checkmate
+		Mate int
+	}
+
+	MoveAnnotation struct {
+		// 1 for the first move of the game
+		Ply int
+
+		// player who made the move, "w" or "b"
+		Color string
+
+		// move in UCI and SAN notations
+		Move string
+		SAN  string
+
+		// engine best move in UCI notation
+		BestMove string
+
+		EvalBefore Eval
+		EvalAfter  Eval
+
+		CentipawnLoss int
+
+		// winning chances lost by the player, from 0 to 100
+		WinChanceDrop float64
+
+		// from 0 to 100
+		Accuracy float64
+
+		Classification Classification
+	}
+
+	PlayerSummary struct {
+		Name string
+
+		// average centipawn loss
+		ACPL float64
+
+		// from 0 to 100
+		Accuracy float64
+
+		// moves by classification
+		Classifications map[Classification]int
+	}
+
+	// Report of a game with its moves annotated
+	Report struct {
+		White PlayerSummary
+		Black PlayerSummary
+		Moves []MoveAnnotation
+	}
+)
+
+// Annotate classifies every move of game. positions are the analyses of game positions
+// as engines make them, positions[i] is the one after i moves with scores from the point
+// of view of its side to move, so there must be one more position than moves.
+func Annotate(game pgn.PGN, positions []uci.Analysis) (Report, error) {
+	if len(positions) != len(game.UCIFormatMoves)+1 {
+		return Report{}, fmt.Errorf("game has %d moves but %d positions were analyzed", len(game.UCIFormatMoves), len(positions))
+	}
+
+	board, err := game.StartingBoard()
+	if err != nil {
+		return Report{}, err
+	}
+
+	before, err := scoreOf(board, positions[0])
+	if err != nil {
+		return Report{}, fmt.Errorf("error evaluating starting position: %w", err)
+	}
+
+	report := Report{Moves: make([]MoveAnnotation, 0, len(game.UCIFormatMoves))}
+	for i, move := range game.UCIFormatMoves {
+		m, err := board.ParseMove(move)
+		if err != nil {
+			return Report{}, fmt.Errorf("error calling board.ParseMove: %w", err)
+		}
+
+		color, san, sacrifice := board.Turn, board.SAN(m), board.SEE(m) < 0
+		board.Play(m)
+
+		after, err := scoreOf(board, positions[i+1])
+		if err != nil {
+			return Report{}, fmt.Errorf("error evaluating position after %d moves: %w", i+1, err)
+		}
+
+		j := judgement{
+			played:    uci.FormatMove(m),
+			best:      positions[i].BestMove,
+			before:    before,
+			after:     opposite(after),
+			sacrifice: sacrifice,
+		}
+
+		winBefore, winAfter := winPercent(centipawns(j.before)), winPercent(centipawns(j.after))
+		report.Moves = append(report.Moves, MoveAnnotation{
+			Ply:            i + 1,
+			Color:          color,
+			Move:           j.played,
+			SAN:            san,
+			BestMove:       j.best,
+			EvalBefore:     whiteEval(before, color),
+			EvalAfter:      whiteEval(after, board.Turn),
+			CentipawnLoss:  j.centipawnLoss(),
+			WinChanceDrop:  round(j.winChanceDrop()),
+			Accuracy:       round(moveAccuracy(winBefore, winAfter)),
+			Classification: j.classify(),
+		})
+
+		before = after
+	}
+
+	report.White = summary(game.White, "w", report.Moves)
+	report.Black = summary(game.Black, "b", report.Moves)
+	return report, nil
+}
+
+// score of the best line of a, positions without legal moves may not have any
+func scoreOf(board chess.Board, a uci.Analysis) (search.Score, error) {
+	if len(a.Lines) > 0 {
+		return a.Lines[0].Score, nil
+	}
+
+	switch board.Outcome().Termination {
+	case chess.Checkmate:
+		return search.Score{IsMate: true}, nil
+	case chess.NoTermination:
+		return search.Score{}, fmt.Errorf("there is no score")
+	}
+
+	return search.Score{}, nil
+}
+
+// s from the point of view of the other player. a player who is mated sees
+// a mate 0 that is a mate given for its opponent.
+func opposite(s search.Score) search.Score {
+	return search.Score{Centipawns: -s.Centipawns, IsMate: s.IsMate, Mate: -s.Mate}
+}
+
+// s from white point of view, turn is the player s belongs to
+func whiteEval(s search.Score, turn string) Eval {
+	if turn == "b" {
+		s = opposite(s)
+	}
+
+	return Eval{Centipawns: s.Centipawns, IsMate: s.IsMate, Mate: s.Mate}
+}
+
+// ACPL and accuracy of the moves of color, accuracy is the average of the arithmetic
+// and harmonic means of moves accuracy, so a few bad moves weigh more than in a plain average
+func summary(name, color string, moves []MoveAnnotation) PlayerSummary {
+	s := PlayerSummary{Name: name, Classifications: map[Classification]int{}}
+
+	count, loss, accuracy, inverse := 0, 0, 0.0, 0.0
+	for _, m := range moves {
+		if m.Color != color {
+			continue
+		}
+
+		count++
+		loss += m.CentipawnLoss
+		accuracy += m.Accuracy
+		inverse += 1 / math.Max(m.Accuracy, 1)
+		s.Classifications[m.Classification]++
+	}
+
+	if count == 0 {
+		return s
+	}
+
+	mean := accuracy / float64(count)
+	harmonic := float64(count) / inverse
+	s.ACPL = round(float64(loss) / float64(count))
+	s.Accuracy = round((mean + harmonic) / 2)
+	return s
+}
+
+// rounds n to one decimal
+func round(n float64) float64 {
+	return math.Round(n*10) / 10
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"testing"
+
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/services/internal/uci"
+	"github.com/stretchr/testify/assert"
+)
+
+func analysisOf(best string, score search.Score) uci.Analysis {
+	return uci.Analysis{BestMove: best, Lines: []uci.Info{{Depth: 10, MultiPV: 1, Score: score}}}
+}
+
+func Test_Annotate(t *testing.T) {
+	game := pgn.PGN{
+		White:          "alice",
+		Black:          "bob",
+		UCIFormatMoves: []string{"e2e4", "e7e5", "d1h5", "b8c6", "f1c4", "g8f6", "h5f7"},
+	}
+
+	positions := []uci.Analysis{
+		analysisOf("e2e4", search.Score{Centipawns: 20}),
+		analysisOf("e7e5", search.Score{Centipawns: -20}),
+		analysisOf("g1f3", search.Score{Centipawns: 25}),
+		analysisOf("b8c6", search.Score{Centipawns: 0}),
+		analysisOf("f1c4", search.Score{Centipawns: 30}),
+		analysisOf("d8e7", search.Score{Centipawns: -50}),
+		analysisOf("h5f7", search.Score{IsMate: true, Mate: 1}),
+		{},
+	}
+
+	report, err := Annotate(game, positions)
+	assert.Nil(t, err)
+	assert.Len(t, report.Moves, 7)
+
+	classifications := []Classification{}
+	for _, m := range report.Moves {
+		classifications = append(classifications, m.Classification)
+	}
+	assert.Equal(t, []Classification{Best, Best, Good, Best, Best, Blunder, Best}, classifications)
+
+	blunder := report.Moves[5]
+	assert.Equal(t, MoveAnnotation{
+		Ply:            6,
+		Color:          "b",
+		Move:           "g8f6",
+		SAN:            "Nf6",
+		BestMove:       "d8e7",
+		EvalBefore:     Eval{Centipawns: 50},
+		EvalAfter:      Eval{IsMate: true, Mate: 1},
+		CentipawnLoss:  950,
+		WinChanceDrop:  43,
+		Accuracy:       12.7,
+		Classification: Blunder,
+	}, blunder)
+
+	mate := report.Moves[6]
+	assert.Equal(t, "Qxf7#", mate.SAN)
+	assert.Equal(t, Eval{IsMate: true, Mate: 0}, mate.EvalAfter)
+	assert.Equal(t, 0, mate.CentipawnLoss)
+
+	assert.Equal(t, "alice", report.White.Name)
+	assert.Equal(t, map[Classification]int{Best: 3, Good: 1}, report.White.Classifications)
+	assert.Equal(t, 6.3, report.White.ACPL)
+	assert.Equal(t, map[Classification]int{Best: 2, Blunder: 1}, report.Black.Classifications)
+	assert.Equal(t, 328.3, report.Black.ACPL)
+	assert.Greater(t, report.White.Accuracy, report.Black.Accuracy)
+}
+
+func Test_Annotate_Errors(t *testing.T) {
+	game := pgn.PGN{UCIFormatMoves: []string{"e2e4"}}
+
+	_, err := Annotate(game, []uci.Analysis{{}})
+	assert.EqualError(t, err, "game has 1 moves but 1 positions were analyzed")
+
+	_, err = Annotate(game, []uci.Analysis{analysisOf("e2e4", search.Score{}), {}})
+	assert.EqualError(t, err, "error evaluating position after 1 moves: there is no score")
+
+	game.UCIFormatMoves = []string{"e2e5"}
+	_, err = Annotate(game, []uci.Analysis{analysisOf("e2e4", search.Score{}), {}})
+	assert.ErrorContains(t, err, "e2e5 is not a legal move")
+}
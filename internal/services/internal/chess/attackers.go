@@ -0,0 +1,143 @@
+package chess
+
+// AttackersOf returns squares of the pieces of color by attacking square, like ["c3", "f3"].
+// by must be "w" or "b" like in FEN. returns nil if square is not valid.
+func (board Board) AttackersOf(square, by string) []string {
+	s, ok := parseSquare(square)
+	if !ok {
+		return nil
+	}
+
+	p := &board.position
+	return squareNames(p.attackersTo(s, p.occupied()) & p.colors[colorOf(by)])
+}
+
+// IsAttacked returns true if any piece of color by attacks square.
+// by must be "w" or "b" like in FEN.
+func (board Board) IsAttacked(square, by string) bool {
+	s, ok := parseSquare(square)
+	return ok && board.position.isAttacked(s, colorOf(by))
+}
+
+// Pinned returns squares of the pieces of color turn that can not leave the line
+// between their king and an opponent rook, bishop or queen without exposing the king.
+func (board Board) Pinned(turn string) []string {
+	return squareNames(board.position.pinned(colorOf(turn)))
+}
+
+// Checkers returns squares of the pieces giving check to board.Turn king.
+func (board Board) Checkers() []string {
+	return squareNames(board.position.checkers())
+}
+
+// Attacks returns squares attacked by the piece on square, with own pieces included
+// since they are defended. returns nil if square is not valid or it is empty.
+func (board Board) Attacks(square string) []string {
+	s, ok := parseSquare(square)
+	if !ok {
+		return nil
+	}
+
+	if board.position.squares[s] == noPiece {
+		return nil
+	}
+
+	return squareNames(board.position.pieceAttacks(s))
+}
+
+// squares attacked by the piece on s, empty if there is no piece
+func (p *position) pieceAttacks(s square) bitboard {
+	pc := p.squares[s]
+	if pc == noPiece {
+		return 0
+	}
+
+	if pc.kind() == pawn {
+		return pawnAttacks[pc.color()][s]
+	}
+
+	return p.attacksFrom(pc.kind(), s, p.occupied())
+}
+
+// returns color of a FEN turn, "w" or "b"
+func colorOf(turn string) color {
+	if turn == "b" {
+		return black
+	}
+
+	return white
+}
+
+// names of squares in b from a1 to h8
+func squareNames(b bitboard) []string {
+	names := make([]string, 0, b.count())
+	for b != 0 {
+		names = append(names, b.pop().String())
+	}
+
+	return names
+}
+
+// pieces of color c between their king and an opponent slider that would attack it otherwise
+func (p *position) pinned(c color) bitboard {
+	k := p.kingSquare(c)
+	if k == noSquare {
+		return 0
+	}
+
+	them := c.other()
+	queens := p.bitboardOf(them, queen)
+	snipers := rookAttacks(k, 0)&(p.bitboardOf(them, rook)|queens) |
+		bishopAttacks(k, 0)&(p.bitboardOf(them, bishop)|queens)
+
+	pinned := bitboard(0)
+	for snipers != 0 {
+		blockers := betweenSquares[k][snipers.pop()] & p.occupied()
+		if blockers.count() == 1 && blockers&p.colors[c] != 0 {
+			pinned |= blockers
+		}
+	}
+
+	return pinned
+}
+
+// opponent pieces attacking side to move king
+func (p *position) checkers() bitboard {
+	k := p.kingSquare(p.side)
+	if k == noSquare {
+		return 0
+	}
+
+	return p.attackersTo(k, p.occupied()) & p.colors[p.side.other()]
+}
+
+// returns true if m does not leave own king attacked. it uses pinned pieces and checkers
+// of side to move instead of making m, castles, en passant captures and drops are made.
+func (p *position) isSafe(m Move, pinned, checkers bitboard) bool {
+	us := p.side
+	k := p.kingSquare(us)
+	if k == noSquare {
+		return true
+	}
+
+	if m.flags&(flagCastle|flagEnPassant|flagDrop) != 0 {
+		return p.isLegal(m)
+	}
+
+	if m.from == k {
+		return p.attackersTo(m.to, p.occupied()&^k.bitboard())&p.colors[us.other()] == 0
+	}
+
+	if checkers.count() > 1 {
+		return false
+	}
+
+	if checkers != 0 {
+		checker := checkers.first()
+		if m.to != checker && !betweenSquares[k][checker].has(m.to) {
+			return false
+		}
+	}
+
+	return !pinned.has(m.from) || lineSquares[k][m.from].has(m.to)
+}
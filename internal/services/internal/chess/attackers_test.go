@@ -0,0 +1,67 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AttackersOf(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/3p4/8/2N2N2/8/3RK3 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"d1", "f3"}, board.AttackersOf("d4", "w"))
+	assert.Equal(t, []string{"d1", "c3"}, board.AttackersOf("d5", "w"))
+	assert.Equal(t, []string{"d5"}, board.AttackersOf("e4", "b"))
+	assert.Empty(t, board.AttackersOf("h8", "w"))
+	assert.Nil(t, board.AttackersOf("z9", "w"))
+}
+
+func Test_IsAttacked(t *testing.T) {
+	board := NewBoard()
+
+	assert.True(t, board.IsAttacked("f3", "w"))
+	assert.False(t, board.IsAttacked("e4", "w"))
+	assert.True(t, board.IsAttacked("f6", "b"))
+	assert.False(t, board.IsAttacked("e4", "b"))
+	assert.False(t, board.IsAttacked("invalid", "w"))
+}
+
+func Test_Attacks(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/3p4/8/2N2N2/8/3RK3 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"a1", "b1", "c1", "e1", "d2", "d3", "d4", "d5"}, board.Attacks("d1"))
+	assert.Equal(t, []string{"c4", "e4"}, board.Attacks("d5"))
+	assert.Len(t, board.Attacks("c3"), 8)
+	assert.Nil(t, board.Attacks("e4"))
+	assert.Nil(t, board.Attacks("z9"))
+}
+
+func Test_Pinned(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/4r3/8/8/1b6/2N5/4B3/4K3 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"e2", "c3"}, board.Pinned("w"))
+	assert.Empty(t, board.Pinned("b"))
+
+	// pinned knight can not move, pinned bishop can not leave e file
+	moves := board.AvailableLegalMoves()
+	assert.NotContains(t, moves, "c3d5")
+	assert.NotContains(t, moves, "e2d3")
+	assert.Contains(t, moves, "e1f2")
+}
+
+func Test_Checkers(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/8/1b6/8/8/R3K2r w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"h1", "b4"}, board.Checkers())
+	assert.ElementsMatch(t, []string{"e1e2", "e1f2"}, board.AvailableLegalMoves())
+
+	assert.Empty(t, NewBoard().Checkers())
+}
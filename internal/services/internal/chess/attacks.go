@@ -0,0 +1,164 @@
+package chess
+
+import (
+	"fmt"
+)
+
+type (
+	// magic bitboard entry for a sliding piece on one square.
+	// index in attacks table is ((occupied & mask) * number) >> shift
+	magic struct {
+		mask    bitboard
+		number  uint64
+		shift   uint
+		attacks []bitboard
+	}
+)
+
+var (
+	knightAttacks [64]bitboard
+	kingAttacks   [64]bitboard
+	pawnAttacks   [2][64]bitboard
+
+	rookMagics   [64]magic
+	bishopMagics [64]magic
+
+	// squares strictly between two squares of the same rank, file or diagonal,
+	// empty if they are not aligned
+	betweenSquares [64][64]bitboard
+
+	// whole rank, file or diagonal going through two squares, empty if they are not aligned
+	lineSquares [64][64]bitboard
+
+	knightOffsets    = [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingOffsets      = [][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+	rookDirections   = [][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}}
+	bishopDirections = [][2]int{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}
+)
+
+func init() {
+	for s := square(0); s < 64; s++ {
+		knightAttacks[s] = leaperAttacks(s, knightOffsets)
+		kingAttacks[s] = leaperAttacks(s, kingOffsets)
+		pawnAttacks[white][s] = leaperAttacks(s, [][2]int{{-1, 1}, {1, 1}})
+		pawnAttacks[black][s] = leaperAttacks(s, [][2]int{{-1, -1}, {1, -1}})
+		rookMagics[s] = newMagic(s, rookDirections, rookMagicNumbers[s])
+		bishopMagics[s] = newMagic(s, bishopDirections, bishopMagicNumbers[s])
+	}
+
+	for a := square(0); a < 64; a++ {
+		for b := square(0); b < 64; b++ {
+			if a == b {
+				continue
+			}
+
+			ends := a.bitboard() | b.bitboard()
+			for _, attacks := range []func(square, bitboard) bitboard{rookAttacks, bishopAttacks} {
+				if !attacks(a, 0).has(b) {
+					continue
+				}
+
+				betweenSquares[a][b] = attacks(a, b.bitboard()) & attacks(b, a.bitboard())
+				lineSquares[a][b] = attacks(a, 0)&attacks(b, 0) | ends
+			}
+		}
+	}
+}
+
+func rookAttacks(s square, occupied bitboard) bitboard {
+	m := &rookMagics[s]
+	return m.attacks[m.index(occupied)]
+}
+
+func bishopAttacks(s square, occupied bitboard) bitboard {
+	m := &bishopMagics[s]
+	return m.attacks[m.index(occupied)]
+}
+
+func queenAttacks(s square, occupied bitboard) bitboard {
+	return rookAttacks(s, occupied) | bishopAttacks(s, occupied)
+}
+
+func (m *magic) index(occupied bitboard) uint64 {
+	return (uint64(occupied&m.mask) * m.number) >> m.shift
+}
+
+func leaperAttacks(s square, offsets [][2]int) bitboard {
+	attacks := bitboard(0)
+	for _, o := range offsets {
+		file, rank := s.file()+o[0], s.rank()+o[1]
+		if file < 0 || file > 7 || rank < 0 || rank > 7 {
+			continue
+		}
+
+		attacks |= newSquare(file, rank).bitboard()
+	}
+
+	return attacks
+}
+
+// walks every direction from s until board limit or first occupied square (included).
+// it is slow, only used to fill magic tables.
+func slidingAttacks(s square, occupied bitboard, directions [][2]int) bitboard {
+	attacks := bitboard(0)
+	for _, d := range directions {
+		file, rank := s.file()+d[0], s.rank()+d[1]
+		for file >= 0 && file < 8 && rank >= 0 && rank < 8 {
+			target := newSquare(file, rank)
+			attacks |= target.bitboard()
+			if occupied.has(target) {
+				break
+			}
+
+			file += d[0]
+			rank += d[1]
+		}
+	}
+
+	return attacks
+}
+
+// squares whose occupancy changes the attacks of a slider in s.
+// last square of each direction is not relevant because it is attacked anyway.
+func relevantOccupancy(s square, directions [][2]int) bitboard {
+	mask := bitboard(0)
+	for _, d := range directions {
+		file, rank := s.file()+d[0], s.rank()+d[1]
+		for file+d[0] >= 0 && file+d[0] < 8 && rank+d[1] >= 0 && rank+d[1] < 8 {
+			mask |= newSquare(file, rank).bitboard()
+			file += d[0]
+			rank += d[1]
+		}
+	}
+
+	return mask
+}
+
+// builds the attacks table of a slider in s indexed by number.
+// it panics if number is not a magic number for s.
+func newMagic(s square, directions [][2]int, number uint64) magic {
+	mask := relevantOccupancy(s, directions)
+	relevantBits := mask.count()
+	m := magic{mask: mask, number: number, shift: uint(64 - relevantBits),
+		attacks: make([]bitboard, 1<<relevantBits)}
+
+	filled := make([]bool, len(m.attacks))
+	subset := bitboard(0)
+	for {
+		attacks := slidingAttacks(s, subset, directions)
+		idx := m.index(subset)
+		if filled[idx] && m.attacks[idx] != attacks {
+			panic(fmt.Sprintf("chess: %x is not a magic number for %s", number, s))
+		}
+
+		filled[idx] = true
+		m.attacks[idx] = attacks
+
+		subset = (subset - mask) & mask
+		if subset == 0 {
+			break
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,59 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rookAttacks_MatchSlidingAttacks(t *testing.T) {
+	occupancies := []bitboard{0, 0xffff00000000ffff, 0x0000402018040200, 0x8142241818244281, 0x00ff00ff00ff00ff}
+
+	for s := square(0); s < 64; s++ {
+		for _, occupied := range occupancies {
+			assert.Equal(t, slidingAttacks(s, occupied, rookDirections), rookAttacks(s, occupied), s.String())
+		}
+	}
+}
+
+func Test_bishopAttacks_MatchSlidingAttacks(t *testing.T) {
+	occupancies := []bitboard{0, 0xffff00000000ffff, 0x0000402018040200, 0x8142241818244281, 0x00ff00ff00ff00ff}
+
+	for s := square(0); s < 64; s++ {
+		for _, occupied := range occupancies {
+			assert.Equal(t, slidingAttacks(s, occupied, bishopDirections), bishopAttacks(s, occupied), s.String())
+		}
+	}
+}
+
+func Test_knightAttacks(t *testing.T) {
+	d4, _ := parseSquare("d4")
+	a1, _ := parseSquare("a1")
+
+	assert.Equal(t, 8, knightAttacks[d4].count())
+	assert.Equal(t, 2, knightAttacks[a1].count())
+}
+
+func Test_pawnAttacks(t *testing.T) {
+	e4, _ := parseSquare("e4")
+	d5, _ := parseSquare("d5")
+	f3, _ := parseSquare("f3")
+
+	assert.True(t, pawnAttacks[white][e4].has(d5))
+	assert.True(t, pawnAttacks[black][e4].has(f3))
+	assert.False(t, pawnAttacks[white][e4].has(f3))
+}
+
+func Test_betweenAndLineSquares(t *testing.T) {
+	a1, _ := parseSquare("a1")
+	d4, _ := parseSquare("d4")
+	h8, _ := parseSquare("h8")
+	b3, _ := parseSquare("b3")
+
+	assert.Equal(t, 2, betweenSquares[a1][d4].count())
+	assert.Equal(t, betweenSquares[a1][d4], betweenSquares[d4][a1])
+	assert.True(t, lineSquares[a1][d4].has(h8))
+	assert.Equal(t, 8, lineSquares[a1][d4].count())
+	assert.Zero(t, betweenSquares[a1][b3])
+	assert.Zero(t, lineSquares[a1][b3])
+}
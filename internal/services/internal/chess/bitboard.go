@@ -0,0 +1,90 @@
+package chess
+
+import (
+	"math/bits"
+)
+
+type (
+	// bitboard is a set of squares, bit 0 is a1 and bit 63 is h8.
+	bitboard uint64
+
+	// square index from 0 (a1) to 63 (h8), rank major.
+	square int8
+)
+
+const (
+	noSquare square = -1
+
+	fileA bitboard = 0x0101010101010101
+	fileB bitboard = fileA << 1
+	fileG bitboard = fileA << 6
+	fileH bitboard = fileA << 7
+
+	rank1 bitboard = 0xff
+	rank2 bitboard = rank1 << 8
+	rank4 bitboard = rank1 << 24
+	rank5 bitboard = rank1 << 32
+	rank7 bitboard = rank1 << 48
+	rank8 bitboard = rank1 << 56
+)
+
+func newSquare(file, rank int) square {
+	return square(rank*8 + file)
+}
+
+func (s square) file() int {
+	return int(s) & 7
+}
+
+func (s square) rank() int {
+	return int(s) >> 3
+}
+
+func (s square) bitboard() bitboard {
+	return bitboard(1) << uint(s)
+}
+
+// returns square in algebraic notation like "e4", or "-" if square is noSquare
+func (s square) String() string {
+	if s < 0 || s > 63 {
+		return "-"
+	}
+
+	return columnLetter[s.file()] + rowNumber[7-s.rank()]
+}
+
+// parse a square in algebraic notation like "e4".
+// returns false if str is not a square.
+func parseSquare(str string) (square, bool) {
+	if len(str) != 2 {
+		return noSquare, false
+	}
+
+	file := int(str[0]) - 'a'
+	rank := int(str[1]) - '1'
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return noSquare, false
+	}
+
+	return newSquare(file, rank), true
+}
+
+func (b bitboard) count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// returns the lowest square of bitboard, bitboard must not be empty
+func (b bitboard) first() square {
+	return square(bits.TrailingZeros64(uint64(b)))
+}
+
+// removes and returns the lowest square of bitboard
+func (b *bitboard) pop() square {
+	s := b.first()
+	*b &= *b - 1
+	return s
+}
+
+func (b bitboard) has(s square) bool {
+	return b&s.bitboard() != 0
+}
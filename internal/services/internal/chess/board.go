@@ -16,6 +16,31 @@ type (
 		AvailableCastles string
 		IsCheck          bool
 		MovesHistory     []string
+
+		// bitboards used for move generation, board field is kept
+		// in sync as a mailbox to look pieces up by square.
+		position position
+
+		// state before every movement made, last one is the most recent
+		undoStack []undo
+
+		// hashes of positions reached before the current one, oldest first
+		history []uint64
+
+		// Chess960 rules asked for with SetChess960, FENs are read with them
+		chess960 bool
+	}
+
+	// everything MakeMove changes and UnmakeMove has to restore
+	undo struct {
+		position         position
+		move             Move
+		turn             string
+		movesCount       int
+		halfMoves        int
+		inPasantSquare   string
+		availableCastles string
+		isCheck          bool
 	}
 )
 
@@ -26,14 +51,6 @@ var (
 	rowNumber map[int]string = map[int]string{
 		0: "8", 1: "7", 2: "6", 3: "5", 4: "4", 5: "3", 6: "2", 7: "1",
 	}
-
-	slidingPieces map[Piece]bool = map[Piece]bool{
-		WBishop: true, WRook: true, BBishop: true, BRook: true,
-		WQueen: true, BQueen: true, WKing: true, BKing: true,
-	}
-
-	whiteCoronationPieces []string = []string{"Q", "R", "N", "B"}
-	blackCoronationPieces []string = []string{"q", "r", "n", "b"}
 )
 
 func NewBoard() Board {
@@ -42,67 +59,110 @@ func NewBoard() Board {
 	return b
 }
 
+// apply a movement like "e2e4" or "e7e8Q" to the board.
+// movement legality is not checked, invalid movements are ignored.
 func (board *Board) MakeMove(movement string) {
-	originSquare, targetSquare, coronationPiece := extractSquaresFromMovement(movement)
-
-	xOrigin, yOrigin := generateXYFromSquare(originSquare)
-	xTarget, yTarget := generateXYFromSquare(targetSquare)
+	m, ok := board.position.parseMove(movement)
+	if !ok {
+		return
+	}
 
-	board.MovesHistory = append(board.MovesHistory, movement)
-	board.move(xOrigin, yOrigin, xTarget, yTarget, Piece(coronationPiece))
-	board.updateAvailableCastles()
+	board.Play(m)
 }
 
-// calculate all available legal moves for board.Turn color.
-// return: a slice with content if legal moves exist, empty slice if is stalemate or nil if is checkmate
-func (board Board) AvailableLegalMoves() []string {
-	king := WKing
-	if board.Turn == "b" {
-		king = BKing
+// ParseMove translates a movement like "e2e4" or "e7e8Q" to a legal move of board position.
+func (board Board) ParseMove(movement string) (Move, error) {
+	m, ok := board.position.parseMove(movement)
+	if !ok {
+		return Move{}, fmt.Errorf("invalid movement %q", movement)
 	}
 
-	availableMoves := board.availableMoves()
-	legalMovements := []string{}
+	for _, legal := range board.position.legalMoves() {
+		if legal == m {
+			return board.withCheck(m), nil
+		}
+	}
 
-	for _, move := range availableMoves {
-		legalMove := true
+	return Move{}, fmt.Errorf("%s is not a legal move", movement)
+}
 
-		boardPlayground := board.copyBoard()
-		boardPlayground.MakeMove(move)
-		opponentAvailableMoves := boardPlayground.availableMoves()
-		for _, opMove := range opponentAvailableMoves {
-			_, opTargetSquare, _ := extractSquaresFromMovement(opMove)
+// apply m to the board saving its UCI string in MovesHistory.
+// m legality is not checked, it must be a move of board position.
+func (board *Board) Play(m Move) {
+	board.undoStack = append(board.undoStack, undo{
+		position:         board.position,
+		move:             m,
+		turn:             board.Turn,
+		movesCount:       board.MovesCount,
+		halfMoves:        board.HalfMoves,
+		inPasantSquare:   board.InPasantSquare,
+		availableCastles: board.AvailableCastles,
+		isCheck:          board.IsCheck,
+	})
+
+	us := board.position.side
+	board.history = append(board.history, board.position.hash)
+	board.MovesHistory = append(board.MovesHistory, m.String())
+	board.position.makeMove(m)
+	board.syncFields()
+	board.syncSquares(m, us)
+}
 
-			kingSquare := boardPlayground.WhereIs(king)[0]
-			// if opponent has a movement where target square is my king
-			// my move was with my king under attack, ergo ilegal.
-			if opTargetSquare == kingSquare {
-				legalMove = false
-				break
-			}
+// take back the last movement made with MakeMove, restoring the board
+// exactly as it was before, MovesHistory included.
+// returns an error if there is no movement to take back.
+func (board *Board) UnmakeMove() error {
+	if len(board.undoStack) == 0 {
+		return fmt.Errorf("there is no movement to unmake")
+	}
+
+	u := board.undoStack[len(board.undoStack)-1]
+	board.undoStack = board.undoStack[:len(board.undoStack)-1]
+	board.MovesHistory = board.MovesHistory[:len(board.MovesHistory)-1]
+	if len(board.history) > 0 {
+		board.history = board.history[:len(board.history)-1]
+	}
+
+	board.position = u.position
+	board.Turn = u.turn
+	board.MovesCount = u.movesCount
+	board.HalfMoves = u.halfMoves
+	board.InPasantSquare = u.inPasantSquare
+	board.AvailableCastles = u.availableCastles
+	board.IsCheck = u.isCheck
+	board.syncSquares(u.move, board.position.side)
+	return nil
+}
 
-			originSquare, targetSquare, _ := extractSquaresFromMovement(move)
-			x, y := generateXYFromSquare(originSquare)
-			if isCastleMovement(board.board[y][x], originSquare, targetSquare) &&
-				(board.isCheck() || targetSquareIsInKingWayInCastle(targetSquare)) {
-				legalMove = false
-				break
-			}
+// LegalMoves returns all legal moves for board.Turn color, empty if it is checkmate or stalemate.
+func (board Board) LegalMoves() []Move {
+	moves := board.position.legalMoves()
+	for i, m := range moves {
+		moves[i] = board.withCheck(m)
+	}
 
-			boardPlayground = board.copyBoard()
-			boardPlayground.MakeMove(move)
-		}
+	return moves
+}
 
-		if legalMove {
-			legalMovements = append(legalMovements, move)
-		}
-	}
+// LegalMovesWithoutChecks returns the moves of LegalMoves without their check flag, so they are
+// generated without making each one. board.IsCheck tells it after one of them is played.
+func (board Board) LegalMovesWithoutChecks() []Move {
+	return board.position.legalMoves()
+}
+
+// calculate all available legal moves for board.Turn color.
+// return: a slice with content if legal moves exist, empty slice if is stalemate or nil if is checkmate
+func (board Board) AvailableLegalMoves() []string {
+	moves := board.LegalMoves()
 
 	// if are not legal movements king could be in mate or stalemate
-	if len(legalMovements) == 0 {
-		if board.isCheck() {
-			return nil
-		}
+	if len(moves) == 0 && board.position.inCheck() {
+		return nil
+	}
+
+	legalMovements := make([]string, 0, len(moves))
+	for _, m := range moves {
+		legalMovements = append(legalMovements, m.String())
 	}
 
 	return legalMovements
@@ -114,7 +174,7 @@ func (b Board) FEN() string {
 	emptySquares := 0
 	for i := range b.board {
 		f := ""
-		for _, square := range b.board[i] {
+		for x, square := range b.board[i] {
 			if square == "" {
 				emptySquares++
 				continue
@@ -126,6 +186,11 @@ func (b Board) FEN() string {
 			}
 			emptySquares = 0
 
+			// Crazyhouse promoted pieces
+			if b.position.promoted.has(newSquare(x, 7-i)) {
+				appending += "~"
+			}
+
 			f += appending
 		}
 
@@ -137,488 +202,172 @@ func (b Board) FEN() string {
 		fen += f + "/"
 	}
 
-	return fmt.Sprintf("%s %s %s %s %d %d", strings.Trim(fen, "/"), b.Turn, b.AvailableCastles,
-		b.InPasantSquare, b.HalfMoves, b.MovesCount)
-}
-
-// given a FEN function translate it to a board.
-// this function will change your board.
-func (board *Board) TranslateFEN(FEN string) error {
-	rows := strings.Split(FEN, "/")
-	if len(rows) != 8 {
-		return fmt.Errorf("invalid FEN")
-	}
-
-	b := [][]Piece{}
-	for x := 0; x < 8; x++ {
-		row := []Piece{}
-		var additionalInformation []string
-		if x == 7 {
-			additionalInformation = strings.Split(rows[x], " ")
-			rows[x] = additionalInformation[0]
-			additionalInformation = additionalInformation[1:]
-		}
-
-		for y := 0; y < 8; y++ {
-			c := string(rows[x][0])
-			rows[x] = rows[x][1:]
-
-			n, err := strconv.Atoi(c)
-			if err != nil {
-				row = append(row, Piece(c))
-				continue
-			}
-
-			row = append(row, "")
-			n--
-			if n > 0 {
-				rows[x] = fmt.Sprintf("%d%s", n, rows[x])
-			}
-		}
-
-		b = append(b, row)
-
-		if additionalInformation != nil {
-			err := board.setAdditionalProperties(additionalInformation)
-			if err != nil {
-				return err
-			}
-		}
+	placement := strings.Trim(fen, "/")
+	if b.position.variant == Crazyhouse {
+		placement += "[" + b.position.pocketField() + "]"
 	}
 
-	board.board = b
-	return nil
-}
-
-func (b Board) WhereIs(p Piece) []string {
-	squares := []string{}
-	for y, row := range b.board {
-		for x, piece := range row {
-			if piece != p {
-				continue
-			}
-
-			squares = append(squares, generateSquare(x, y))
-		}
+	castles := b.AvailableCastles
+	if castles == "" {
+		castles = "-"
 	}
 
-	return squares
-}
-
-func (b Board) GetPieceAt(square string) Piece {
-	x, y := generateXYFromSquare(square)
-	return b.board[y][x]
-}
-
-// give all possible moves (legal or not)
-func (b Board) availableMoves() []string {
-	moves := ""
-	for y, row := range b.board {
-		for x, piece := range row {
-			if piece == "" {
-				continue
-			}
-
-			if !piece.IsColor(b.Turn) {
-				continue
-			}
-
-			specialMoves := b.generateSpecialMoves(x, y)
-			if specialMoves != "" {
-				moves = joinStrings(moves, specialMoves)
-			}
-
-			isSlidingPiece := slidingPieces[piece]
-			if isSlidingPiece {
-				slidingMoves := b.generateSlidingPiecesMoves(x, y)
-				moves = joinStrings(moves, slidingMoves)
-				continue
-			}
-
-			if piece == WKnight || piece == BKnight {
-				knightMoves := b.generateKnightMoves(x, y)
-				moves = joinStrings(moves, knightMoves)
-				continue
-			}
-
-			if piece == WPawn || piece == BPawn {
-				pawnMoves := b.generatePawnMoves(x, y)
-				moves = joinStrings(moves, pawnMoves)
-			}
-		}
+	inPassant := b.InPasantSquare
+	if inPassant == "" {
+		inPassant = "-"
 	}
 
-	m := strings.Trim(moves, " ")
-	if m == "" {
-		return nil
+	// Three-check FENs tell how many checks every player still has to give
+	if b.position.variant == ThreeCheck {
+		inPassant += fmt.Sprintf(" %d+%d", checksToWin-b.position.checks[white], checksToWin-b.position.checks[black])
 	}
 
-	return strings.Split(m, " ")
+	return fmt.Sprintf("%s %s %s %s %d %d", placement, b.Turn, castles,
+		inPassant, b.HalfMoves, b.MovesCount)
 }
 
-// generate different pieces special moves, except pawn transformation
-// because this move is not different to normal pawn movement
-func (board Board) generateSpecialMoves(x, y int) string {
-	piece := board.board[y][x]
-
-	if (piece == WKing || piece == BKing) && board.AvailableCastles != "" {
-		return strings.Trim(board.possibleCastles(x, y), " ")
-	}
-
-	if piece == WPawn || piece == BPawn {
-		return strings.Trim(board.possibleInPassant(x, y), " ")
+// given a FEN function translate it to a board.
+// this function will change your board.
+// returns a FENError if FEN is not valid or the position is not legal,
+// leaving the board unchanged.
+func (board *Board) TranslateFEN(FEN string) error {
+	p, err := board.parsePosition(FEN)
+	if err != nil {
+		return err
 	}
 
-	return ""
+	board.load(p)
+	return nil
 }
 
-func (board Board) generatePawnMoves(x, y int) string {
-	pawn := board.board[y][x]
-
-	isWhiteColor := pawn.IsColor("w")
-
-	yOffset := -1
-	if !isWhiteColor {
-		yOffset = 1
-	}
-
-	squareCount := 1
-	if isWhiteColor && y == 6 || !isWhiteColor && y == 1 {
-		squareCount++
-	}
-
-	moves := ""
-	for i := 1; i <= squareCount; i++ {
-		yTarget := y + yOffset*i
-		if !board.isInsideLimit(x, yTarget) {
-			break
-		}
-
-		target := board.board[yTarget][x]
-		if target != "" {
-			break
-		}
-
-		// if target square is board limit, the movement is a pawn coronation
-		if yTarget == 0 || yTarget == 7 {
-			possibleCoronation := whiteCoronationPieces
-			if !isWhiteColor {
-				possibleCoronation = blackCoronationPieces
-			}
-
-			for _, p := range possibleCoronation {
-				moves += generateTargetMovement(x, y, x, yTarget) + p
-			}
-
-			continue
-		}
-
-		moves += generateTargetMovement(x, y, x, yTarget)
+// parse a FEN to a position legal under board variant and Chess960 rules
+func (board *Board) parsePosition(FEN string) (position, error) {
+	p, err := parseFEN(FEN, board.chess960)
+	if err != nil {
+		return p, err
 	}
 
-	// after look in front of pawn, look for possible captures
-	xTarget := x + 1
-	yTarget := y + yOffset
-	if board.isInsideLimit(xTarget, yTarget) {
-		targetCapture := board.board[yTarget][xTarget]
-		if targetCapture != "" && !targetCapture.AreSameColor(pawn) {
-			moves += generateTargetMovement(x, y, xTarget, yTarget)
-		}
-	}
+	p.variant = board.position.variant
+	return p, p.validate()
+}
 
-	xTarget = x - 1
-	if board.isInsideLimit(xTarget, yTarget) {
-		targetCapture := board.board[yTarget][xTarget]
-		if targetCapture != "" && !targetCapture.AreSameColor(pawn) {
-			moves += generateTargetMovement(x, y, xTarget, yTarget)
+// set p as board position, exported fields are filled like MakeMove does
+func (board *Board) load(p position) {
+	board.position = p
+	board.undoStack = nil
+	board.history = nil
+	board.syncFields()
+
+	board.board = make([][]Piece, 8)
+	for y := range board.board {
+		board.board[y] = make([]Piece, 8)
+		for x := range board.board[y] {
+			board.board[y][x] = p.squares[newSquare(x, 7-y)].symbol()
 		}
 	}
-
-	return strings.Trim(moves, " ")
 }
 
-func (board Board) generateKnightMoves(x, y int) string {
-	knight := board.board[y][x]
-	offsets := map[int][]int{
-		1:  {2, -2},
-		-1: {2, -2},
-		2:  {1, -1},
-		-2: {1, -1},
-	}
-
-	moves := ""
-	for xOffset, offsetsY := range offsets {
-		for _, yOffset := range offsetsY {
-			xTarget := x + xOffset
-			yTarget := y + yOffset
-			if !board.isInsideLimit(xTarget, yTarget) {
-				continue
-			}
-
-			if board.board[yTarget][xTarget].AreSameColor(knight) {
+func (b Board) WhereIs(p Piece) []string {
+	squares := []string{}
+	for y, row := range b.board {
+		for x, piece := range row {
+			if piece != p {
 				continue
 			}
 
-			moves += generateTargetMovement(x, y, xTarget, yTarget)
+			squares = append(squares, generateSquare(x, y))
 		}
 	}
 
-	return strings.Trim(moves, " ")
+	return squares
 }
 
-func (board Board) generateSlidingPiecesMoves(x, y int) string {
-	moves := ""
-	piece := board.board[y][x]
-
-	directionsCount := generateDirectionsCount(piece)
-	for d := 0; d < directionsCount; d++ {
-		offsetFunc := generateOffsetFunction(piece)
-		if offsetFunc == nil {
-			continue
-		}
-
-		xOffset, yOffset := offsetFunc(d)
-		moves += board.lookForMovesAtDirection(x, xOffset, y, yOffset)
+// Count returns how many pieces p there are on board
+func (b Board) Count(p Piece) int {
+	pc := pieceFromSymbol(p)
+	if pc == noPiece {
+		return 0
 	}
 
-	return strings.Trim(moves, " ")
+	return b.position.pieces[pc].count()
 }
 
-func (board Board) lookForMovesAtDirection(x, xOffset, y, yOffset int) string {
-	moves := ""
-	piece := board.board[y][x]
-	xTarget := x + xOffset
-	yTarget := y + yOffset
-	for board.isInsideLimit(xTarget, yTarget) {
-		targetPiece := board.board[yTarget][xTarget]
-
-		// direction blocked by friendly piece
-		if piece.AreSameColor(targetPiece) {
-			break
-		}
-
-		// if target square is blocked by an opponent piece or is empty
-		// it is a valid square to move
-		moves += generateTargetMovement(x, y, xTarget, yTarget)
-
-		// direction blocked by opponent piece
-		if targetPiece != "" {
-			break
-		}
-
-		if piece == WKing || piece == BKing {
-			break
-		}
-
-		xTarget += xOffset
-		yTarget += yOffset
+// Clone returns a copy of board that can be played on without changing board
+func (board Board) Clone() Board {
+	clone := board
+	clone.board = make([][]Piece, len(board.board))
+	for i, row := range board.board {
+		clone.board[i] = append([]Piece(nil), row...)
 	}
 
-	return moves
+	clone.MovesHistory = append([]string(nil), board.MovesHistory...)
+	clone.undoStack = append([]undo(nil), board.undoStack...)
+	clone.history = append([]uint64(nil), board.history...)
+	return clone
 }
 
-func (board Board) possibleCastles(x, y int) string {
-	king := board.board[y][x]
-	moves := ""
-	for _, v := range board.AvailableCastles {
-		side := string(v)
-		possibleCastleSide := Piece(side)
-		if !king.AreSameColor(possibleCastleSide) {
-			continue
-		}
-
-		side = strings.ToLower(side)
-		offset := 1
-		if side == "q" {
-			offset = -1
-		}
-
-		if board.board[y][x+offset] != "" || board.board[y][x+offset*2] != "" {
-			continue
-		}
-
-		moves += generateTargetMovement(x, y, x+offset*2, y)
-	}
-
-	return moves
+func (b Board) GetPieceAt(square string) Piece {
+	x, y := generateXYFromSquare(square)
+	return b.board[y][x]
 }
 
-func (board Board) possibleInPassant(x, y int) string {
-	pawn := board.board[y][x]
-	xOffsets := []int{-1, 1}
-	yOffset := -1
-	if pawn.IsColor("b") {
-		yOffset = 1
+// give all possible moves (legal or not)
+func (b Board) availableMoves() []string {
+	moves := b.position.pseudoLegalMoves(nil)
+	if len(moves) == 0 {
+		return nil
 	}
 
-	for _, xOffset := range xOffsets {
-		xTarget := x + xOffset
-		yTarget := y + yOffset
-		targetSquare := fmt.Sprintf("%s%s", columnLetter[xTarget], rowNumber[yTarget])
-		if board.InPasantSquare == targetSquare {
-			return generateTargetMovement(x, y, xTarget, yTarget)
-		}
+	movements := make([]string, 0, len(moves))
+	for _, m := range moves {
+		movements = append(movements, m.String())
 	}
 
-	return ""
+	return movements
 }
 
-func (board *Board) setAdditionalProperties(additionalInformation []string) error {
-	board.Turn = additionalInformation[0]
-	board.AvailableCastles = additionalInformation[1]
-	board.InPasantSquare = additionalInformation[2]
-
-	halfMoves, err := strconv.Atoi(additionalInformation[3])
-	if err != nil {
-		return fmt.Errorf("invalid FEN")
+// returns m with check flag set if it gives check
+func (board Board) withCheck(m Move) Move {
+	after := board.position
+	after.makeMove(m)
+	if after.inCheck() {
+		m.flags |= flagCheck
 	}
 
-	movesCount, err := strconv.Atoi(additionalInformation[4])
-	if err != nil {
-		return fmt.Errorf("invalid FEN")
-	}
-
-	board.HalfMoves = halfMoves
-	board.MovesCount = movesCount
-	return nil
+	return m
 }
 
-func (board *Board) haveLostCastle(x, y, xTarget, yTarget int) {
-	if board.AvailableCastles == "" {
-		return
-	}
-
-	p := board.board[y][x]
-	if p == WKing || p == BKing || p == WRook || p == BRook {
-		possibleCastles := ""
-		for _, c := range board.AvailableCastles {
-			str := string(c)
-			if !p.AreSameColor(Piece(str)) {
-				possibleCastles += str
-			}
-		}
-	}
-
-	// a8 square
-	if xTarget == 0 && yTarget == 0 {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "q", "", 1)
-		return
-	}
-
-	// h8 square
-	if xTarget == 7 && yTarget == 0 {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "k", "", 1)
-		return
-	}
-
-	// h1 square
-	if xTarget == 7 && yTarget == 7 {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "K", "", 1)
-		return
-	}
+// update exported fields from position
+func (board *Board) syncFields() {
+	p := &board.position
 
-	// a1 square
-	if xTarget == 0 && yTarget == 7 {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "Q", "", 1)
-	}
+	board.Turn = p.side.String()
+	board.MovesCount = p.fullMoves
+	board.HalfMoves = p.halfMoves
+	board.InPasantSquare = p.epSquare.String()
+	board.AvailableCastles = p.castlingField()
+	board.IsCheck = p.inCheck()
 }
 
-func (board *Board) move(x, y, xTarget, yTarget int, coronationPiece Piece) {
-	p := board.board[y][x]
+// update mailbox squares touched by m, made by color us
+func (board *Board) syncSquares(m Move, us color) {
+	p := &board.position
 
-	board.Turn = "b"
-	if p.IsColor("b") {
-		board.MovesCount++
-		board.Turn = "w"
+	changed := []square{m.from, m.to}
+	if m.flags&flagEnPassant != 0 {
+		changed = append(changed, newSquare(m.to.file(), m.from.rank()))
 	}
-
-	board.HalfMoves++
-	if p == WPawn || p == BPawn {
-		board.HalfMoves = 0
-		if y == 6 && yTarget == 4 || y == 1 && yTarget == 3 {
-			offset := 1
-			if p.IsColor("b") {
-				offset = -1
-			}
-
-			board.InPasantSquare = generateSquare(xTarget, yTarget+offset)
-		}
+	if m.flags&flagCastle != 0 {
+		changed = append(changed, m.kingTo(), m.rookTo())
 	}
-
-	board.haveLostCastle(x, y, xTarget, yTarget)
-
-	if coronationPiece != "" {
-		p = Piece(coronationPiece)
-	}
-
-	isCastleMove := isCastleMovement(p, generateSquare(x, y), generateSquare(xTarget, yTarget))
-
-	board.board[y][x] = ""
-	board.board[yTarget][xTarget] = p
-
-	if isCastleMove {
-		rX, rY, rXTarget, rYTarget := getCastleRookPosition(xTarget, yTarget)
-		board.board[rYTarget][rXTarget] = board.board[rY][rX]
-		board.board[rY][rX] = ""
-	}
-}
-
-func (board Board) isCheck() bool {
-	king := WKing
-	b := board.copyBoard()
-	b.Turn = "b"
-	if board.Turn == "b" {
-		b.Turn = "w"
-		king = BKing
-	}
-
-	b.InPasantSquare = ""
-
-	kingSquare := board.WhereIs(king)[0]
-	opponentMoves := b.AvailableLegalMoves()
-	for _, move := range opponentMoves {
-		_, targetSquare, _ := extractSquaresFromMovement(move)
-
-		if targetSquare == kingSquare {
-			return true
+	// Atomic captures explode pieces around target square
+	if m.flags&flagCapture != 0 && p.rules() == Atomic {
+		for around := kingAttacks[m.to]; around != 0; {
+			changed = append(changed, around.pop())
 		}
 	}
 
-	return false
-}
-
-func (board *Board) updateAvailableCastles() {
-	if board.AvailableCastles == "" {
-		return
-	}
-
-	if board.GetPieceAt("e8") != BKing {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "kq", "", 1)
-	}
-	if board.GetPieceAt("e1") != WKing {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "KQ", "", 1)
-	}
-
-	if board.GetPieceAt("a8") != BRook {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "q", "", 1)
-	}
-	if board.GetPieceAt("h8") != BRook {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "k", "", 1)
+	for _, s := range changed {
+		board.board[7-s.rank()][s.file()] = p.squares[s].symbol()
 	}
-	if board.GetPieceAt("a1") != WRook {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "Q", "", 1)
-	}
-	if board.GetPieceAt("h1") != WRook {
-		board.AvailableCastles = strings.Replace(board.AvailableCastles, "K", "", 1)
-	}
-}
-
-func (Board) isInsideLimit(x, y int) bool {
-	return x >= 0 && x < 8 && y >= 0 && y < 8
-}
-
-func (board Board) copyBoard() Board {
-	c := Board{}
-	c.TranslateFEN(board.FEN())
-	return c
 }
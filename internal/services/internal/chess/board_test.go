@@ -45,7 +45,7 @@ func Test_AvailableLegalMoves_InitialPosition(t *testing.T) {
 
 func Test_AvailableLegalMoves_CheckedKing(t *testing.T) {
 	board := Board{}
-	board.TranslateFEN("4k3/4Q3/8/8/8/8/8/8 b - - 0 1")
+	board.TranslateFEN("4k3/4Q3/8/8/8/8/8/K7 b - - 0 1")
 
 	legalMoves := board.AvailableLegalMoves()
 
@@ -55,7 +55,7 @@ func Test_AvailableLegalMoves_CheckedKing(t *testing.T) {
 
 func Test_AvailableLegalMoves_CheckedKingWithSameColorRookInBoard(t *testing.T) {
 	board := Board{}
-	board.TranslateFEN("8/1b6/K7/8/1R6/8/8/8 w - - 0 1")
+	board.TranslateFEN("7k/1b6/K7/8/1R6/8/8/8 w - - 0 1")
 
 	legalMoves := board.AvailableLegalMoves()
 
@@ -64,7 +64,7 @@ func Test_AvailableLegalMoves_CheckedKingWithSameColorRookInBoard(t *testing.T)
 
 func Test_AvailableLegalMoves_CheckedKingWithCastleOpion(t *testing.T) {
 	board := Board{}
-	board.TranslateFEN("1k6/8/8/8/8/6b1/8/R3K2R w - KQ 0 1")
+	board.TranslateFEN("1k6/8/8/8/8/6b1/8/R3K2R w KQ - 0 1")
 
 	legalMoves := board.AvailableLegalMoves()
 
@@ -73,7 +73,7 @@ func Test_AvailableLegalMoves_CheckedKingWithCastleOpion(t *testing.T) {
 
 func Test_AvailableLegalMoves_BishopInKingCastleWay(t *testing.T) {
 	board := Board{}
-	board.TranslateFEN("1r6/8/8/8/8/7b/n7/R3K2R w - KQ 0 1")
+	board.TranslateFEN("1r5k/8/8/8/8/7b/n7/R3K2R w KQ - 0 1")
 
 	legalMoves := board.AvailableLegalMoves()
 
@@ -99,9 +99,142 @@ func Test_AvailableLegalMoves_Stalemate(t *testing.T) {
 	assert.Len(t, legalMoves, 0)
 }
 
-func Test_availableMoves_OnlyPiecesWithDifferentColorOfTurn(t *testing.T) {
+func Test_LegalMoves(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("r3k3/1P6/8/3pP3/8/8/8/4K2R w K d6 0 1")
+
+	moves := map[string]Move{}
+	for _, m := range board.LegalMoves() {
+		moves[m.String()] = m
+	}
+
+	cases := []struct {
+		movement   string
+		piece      Piece
+		captured   Piece
+		promotion  Piece
+		capture    bool
+		castle     bool
+		enPassant  bool
+		doublePush bool
+		check      bool
+	}{
+		{movement: "e5e6", piece: WPawn},
+		{movement: "e5d6", piece: WPawn, captured: BPawn, capture: true, enPassant: true},
+		{movement: "b7a8Q", piece: WPawn, captured: BRook, promotion: WQueen, capture: true, check: true},
+		{movement: "b7b8R", piece: WPawn, promotion: WRook, check: true},
+		{movement: "e1g1", piece: WKing, castle: true},
+		{movement: "h1h8", piece: WRook, check: true},
+	}
+
+	for _, c := range cases {
+		m, ok := moves[c.movement]
+
+		assert.True(t, ok, c.movement)
+		assert.Equal(t, c.movement[:2], m.From(), c.movement)
+		assert.Equal(t, c.movement[2:4], m.To(), c.movement)
+		assert.Equal(t, c.piece, m.Piece(), c.movement)
+		assert.Equal(t, c.captured, m.Captured(), c.movement)
+		assert.Equal(t, c.promotion, m.Promotion(), c.movement)
+		assert.Equal(t, c.capture, m.IsCapture(), c.movement)
+		assert.Equal(t, c.castle, m.IsCastle(), c.movement)
+		assert.Equal(t, c.enPassant, m.IsEnPassant(), c.movement)
+		assert.Equal(t, c.doublePush, m.IsDoublePush(), c.movement)
+		assert.Equal(t, c.check, m.IsCheck(), c.movement)
+	}
+}
+
+func Test_LegalMoves_DoublePush(t *testing.T) {
+	board := NewBoard()
+
+	m, err := board.ParseMove("e2e4")
+
+	assert.Nil(t, err)
+	assert.True(t, m.IsDoublePush())
+	assert.Equal(t, WPawn, m.Piece())
+	assert.Equal(t, Piece(""), m.Captured())
+	assert.Contains(t, board.LegalMoves(), m)
+}
+
+func Test_LegalMovesWithoutChecks(t *testing.T) {
 	board := Board{}
-	board.TranslateFEN("8/8/8/4b3/8/8/8/8 w - - 0 1")
+	board.TranslateFEN("r3k3/1P6/8/3pP3/8/8/8/4K2R w K d6 0 1")
+
+	moves := board.LegalMovesWithoutChecks()
+	checks := board.LegalMoves()
+
+	assert.Len(t, moves, len(checks))
+	for i, m := range moves {
+		assert.False(t, m.IsCheck(), m.String())
+		assert.Equal(t, checks[i].String(), m.String())
+
+		board.Play(m)
+		assert.Equal(t, checks[i].IsCheck(), board.IsCheck, m.String())
+		board.UnmakeMove()
+	}
+}
+
+func Test_ParseMove_NotLegal(t *testing.T) {
+	board := NewBoard()
+
+	_, err := board.ParseMove("e2e5")
+	assert.EqualError(t, err, "e2e5 is not a legal move")
+
+	_, err = board.ParseMove("e2")
+	assert.EqualError(t, err, `invalid movement "e2"`)
+}
+
+func Test_Play(t *testing.T) {
+	board := NewBoard()
+	m, _ := board.ParseMove("g1f3")
+
+	board.Play(m)
+
+	assert.Equal(t, []string{"g1f3"}, board.MovesHistory)
+	assert.Equal(t, Piece("N"), board.GetPieceAt("f3"))
+	assert.Equal(t, "b", board.Turn)
+}
+
+func Test_Count(t *testing.T) {
+	board := NewBoard()
+
+	assert.Equal(t, 8, board.Count(WPawn))
+	assert.Equal(t, 1, board.Count(BQueen))
+	assert.Equal(t, 0, board.Count(Piece("X")))
+}
+
+func Test_Clone(t *testing.T) {
+	board := NewBoard()
+	board.MakeMove("e2e4")
+
+	clone := board.Clone()
+	clone.MakeMove("e7e5")
+	clone.MakeMove("g1f3")
+
+	assert.Equal(t, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", board.FEN())
+	assert.Equal(t, []string{"e2e4"}, board.MovesHistory)
+	assert.Equal(t, Piece(""), board.GetPieceAt("e5"))
+
+	clone.UnmakeMove()
+	clone.UnmakeMove()
+	assert.Equal(t, board.FEN(), clone.FEN())
+}
+
+// builds a board without checking the position is legal,
+// to test move generation of pieces alone
+func boardFromFEN(fen string) Board {
+	p, err := parseFEN(fen, false)
+	if err != nil {
+		panic(err)
+	}
+
+	board := Board{}
+	board.load(p)
+	return board
+}
+
+func Test_availableMoves_OnlyPiecesWithDifferentColorOfTurn(t *testing.T) {
+	board := boardFromFEN("8/8/8/4b3/8/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -109,8 +242,7 @@ func Test_availableMoves_OnlyPiecesWithDifferentColorOfTurn(t *testing.T) {
 }
 
 func Test_availableMoves_Bishop(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/4B3/8/8/8/8 w - - 0 1")
+	board := boardFromFEN("8/8/8/4B3/8/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -119,8 +251,7 @@ func Test_availableMoves_Bishop(t *testing.T) {
 }
 
 func Test_availableMoves_BishopWithOpponentPiece(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/6r1/8/4B3/8/8/8/8 w - - 0 1")
+	board := boardFromFEN("8/6r1/8/4B3/8/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -129,8 +260,7 @@ func Test_availableMoves_BishopWithOpponentPiece(t *testing.T) {
 }
 
 func Test_availableMoves_BishopWithSameColorPiece(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/6R1/8/4B3/8/8/8/8 w - - 0 1")
+	board := boardFromFEN("8/6R1/8/4B3/8/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -141,8 +271,7 @@ func Test_availableMoves_BishopWithSameColorPiece(t *testing.T) {
 }
 
 func Test_availableMoves_Rook(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/8/8/8/6r1/8 b - - 0 1")
+	board := boardFromFEN("8/8/8/8/8/8/6r1/8 b - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -151,8 +280,7 @@ func Test_availableMoves_Rook(t *testing.T) {
 }
 
 func Test_availableMoves_Queen(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/4Q3/8/8/8/8 w - - 0 1")
+	board := boardFromFEN("8/8/8/4Q3/8/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -162,8 +290,7 @@ func Test_availableMoves_Queen(t *testing.T) {
 }
 
 func Test_availableMoves_King(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/8/8/8/2K5/8 w - - 0 1")
+	board := boardFromFEN("8/8/8/8/8/8/2K5/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -171,8 +298,7 @@ func Test_availableMoves_King(t *testing.T) {
 }
 
 func Test_availableMoves_KingCastles(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/8/8/8/8/R3K2R w KQ - 0 1")
+	board := boardFromFEN("8/8/8/8/8/8/8/R3K2R w KQ - 0 1")
 
 	moves := board.availableMoves()
 
@@ -182,8 +308,7 @@ func Test_availableMoves_KingCastles(t *testing.T) {
 }
 
 func Test_availableMoves_Knight(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/8/3N5/8/8/8 w - - 0 1")
+	board := boardFromFEN("8/8/8/8/3N4/8/8/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -191,8 +316,7 @@ func Test_availableMoves_Knight(t *testing.T) {
 }
 
 func Test_availableMoves_Pawn(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/7P/8/4p3/4P3/P5p1/1P3P2/8 w - - 0 1")
+	board := boardFromFEN("8/7P/8/4p3/4P3/P5p1/1P3P2/8 w - - 0 1")
 
 	moves := board.availableMoves()
 
@@ -201,8 +325,7 @@ func Test_availableMoves_Pawn(t *testing.T) {
 }
 
 func Test_availableMoves_PawnInPassant(t *testing.T) {
-	board := Board{}
-	board.TranslateFEN("8/8/8/4pP2/8/8/P7/8 w - e6 0 1")
+	board := boardFromFEN("8/8/8/4pP2/8/8/P7/8 w - e6 0 1")
 
 	moves := board.availableMoves()
 
@@ -233,6 +356,27 @@ func Test_FEN(t *testing.T) {
 	assert.Equal(t, "r3r1k1/pp3nPp/1b1p1B2/1q1P1N2/8/P4Q2/1P3PK1/R6R w - - 1 40", FEN)
 }
 
+func Test_TranslateFEN_FieldsMatchMakeMove(t *testing.T) {
+	for _, fen := range []string{"4k3/8/8/8/8/8/4P3/4K2R w K - 0 1", "4k3/8/8/3p4/8/8/4P3/4K3 w - - 0 1"} {
+		played := Board{}
+		played.TranslateFEN(fen)
+		played.MakeMove("e2e4")
+		played.MakeMove("e8d8")
+		played.MakeMove("e1f1")
+		played.UnmakeMove()
+
+		translated := Board{}
+		err := translated.TranslateFEN(played.FEN())
+		assert.Nil(t, err)
+
+		assert.Equal(t, played.Turn, translated.Turn, fen)
+		assert.Equal(t, played.AvailableCastles, translated.AvailableCastles, fen)
+		assert.Equal(t, played.InPasantSquare, translated.InPasantSquare, fen)
+		assert.Equal(t, played.HalfMoves, translated.HalfMoves, fen)
+		assert.Equal(t, played.MovesCount, translated.MovesCount, fen)
+	}
+}
+
 func Test_TranslateFEN(t *testing.T) {
 	assert := assert.New(t)
 	FEN := "r3r1k1/pp3nPp/1b1p1B2/1q1P1N2/8/P4Q2/1P3PK1/R6R w - - 2 35"
@@ -252,7 +396,7 @@ func Test_TranslateFEN(t *testing.T) {
 		{WRook, "", "", "", "", "", "", WRook},
 	}, b.board)
 	assert.Equal("w", b.Turn)
-	assert.Equal("-", b.AvailableCastles)
+	assert.Empty(b.AvailableCastles)
 	assert.Equal("-", b.InPasantSquare)
 	assert.Equal(2, b.HalfMoves)
 	assert.Equal(35, b.MovesCount)
@@ -280,3 +424,116 @@ func Test_InitialPosition(t *testing.T) {
 	assert.Equal(1, b.MovesCount)
 	assert.Len(b.MovesHistory, 0)
 }
+
+func Test_UnmakeMove(t *testing.T) {
+	assert := assert.New(t)
+	board := NewBoard()
+	initialFEN := board.FEN()
+
+	board.MakeMove("e2e4")
+	afterE4 := board.FEN()
+	board.MakeMove("e7e5")
+
+	err := board.UnmakeMove()
+
+	assert.Nil(err)
+	assert.Equal(afterE4, board.FEN())
+	assert.Equal([]string{"e2e4"}, board.MovesHistory)
+	assert.Equal("b", board.Turn)
+	assert.Equal("e3", board.InPasantSquare)
+
+	err = board.UnmakeMove()
+
+	assert.Nil(err)
+	assert.Equal(initialFEN, board.FEN())
+	assert.Len(board.MovesHistory, 0)
+	assert.Equal(Piece("P"), board.board[6][4])
+	assert.Equal(Piece(""), board.board[4][4])
+}
+
+func Test_UnmakeMove_WithoutMoves(t *testing.T) {
+	board := NewBoard()
+
+	err := board.UnmakeMove()
+
+	assert.EqualError(t, err, "there is no movement to unmake")
+	assert.Equal(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", board.FEN())
+}
+
+func Test_UnmakeMove_SpecialMoves(t *testing.T) {
+	cases := []struct {
+		name string
+		fen  string
+		move string
+	}{
+		{name: "castle", fen: "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 3 10", move: "e1g1"},
+		{name: "long castle", fen: "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 3 10", move: "e8c8"},
+		{name: "in passant", fen: "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 2", move: "e5d6"},
+		{name: "promotion with capture", fen: "1n2k3/P7/8/8/8/8/8/4K3 w - - 5 40", move: "a7b8N"},
+		{name: "rook capture loses castle", fen: "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", move: "a1a8"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			board := Board{}
+			board.TranslateFEN(c.fen)
+			before := board
+
+			board.MakeMove(c.move)
+			err := board.UnmakeMove()
+
+			assert.Nil(err)
+			assert.Equal(c.fen, board.FEN())
+			assert.Equal(before.position, board.position)
+			assert.Equal(before.IsCheck, board.IsCheck)
+			assert.Len(board.MovesHistory, 0)
+		})
+	}
+}
+
+func Test_UnmakeMove_WalkGameBackAndForward(t *testing.T) {
+	assert := assert.New(t)
+	moves := []string{"e2e4", "c7c5", "g1f3", "d7d6", "d2d4", "c5d4", "f3d4", "g8f6", "b1c3", "a7a6",
+		"c1e3", "e7e5", "d4b3", "c8e6", "f2f3", "f8e7", "d1d2", "e8g8", "e1c1", "b8d7"}
+	board := NewBoard()
+	fens := []string{board.FEN()}
+
+	for _, m := range moves {
+		board.MakeMove(m)
+		fens = append(fens, board.FEN())
+	}
+
+	for i := len(moves) - 1; i >= 0; i-- {
+		err := board.UnmakeMove()
+		assert.Nil(err)
+		assert.Equal(fens[i], board.FEN())
+		assert.Equal(moves[:i], board.MovesHistory)
+	}
+
+	for i, m := range moves {
+		board.MakeMove(m)
+		assert.Equal(fens[i+1], board.FEN())
+	}
+}
+
+func Benchmark_AvailableLegalMoves(b *testing.B) {
+	board := Board{}
+	board.TranslateFEN("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.AvailableLegalMoves()
+	}
+}
+
+func Benchmark_MakeMove(b *testing.B) {
+	moves := []string{"e2e4", "e7e5", "g1f3", "b8c6", "f1c4", "g8f6", "e1g1", "f8c5"}
+
+	for i := 0; i < b.N; i++ {
+		board := NewBoard()
+		for _, m := range moves {
+			board.MakeMove(m)
+		}
+	}
+}
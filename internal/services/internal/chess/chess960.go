@@ -0,0 +1,19 @@
+package chess
+
+// SetChess960 marks board as a Chess960 (Fischer Random) game.
+// castles are written with the king taking its own rook in UCI, like "e1h1",
+// and in FEN castling rights use rook files when rooks are not the outermost ones.
+// FENs translated after it read KQkq as the outermost rooks, wherever king and rooks are.
+// boards created from a FEN with Shredder-FEN or X-FEN rook files are already marked.
+func (board *Board) SetChess960(chess960 bool) {
+	board.chess960 = chess960
+	board.position.chess960 = chess960
+	if board.AvailableCastles != "" && board.AvailableCastles != "-" {
+		board.AvailableCastles = board.position.castlingField()
+	}
+}
+
+// IsChess960 returns true if board is a Chess960 game
+func (board Board) IsChess960() bool {
+	return board.position.chess960
+}
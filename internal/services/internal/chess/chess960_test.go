@@ -0,0 +1,162 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TranslateFEN_Chess960(t *testing.T) {
+	cases := []struct {
+		fen      string
+		chess960 bool
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w HAha - 0 1", true},
+		{"bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", true},
+		{"rk2r3/pppppppp/8/8/8/8/PPPPPPPP/RK2R3 w EAea - 0 1", true},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", false},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		err := board.TranslateFEN(c.fen)
+
+		assert.Nil(t, err, c.fen)
+		assert.Equal(t, c.chess960, board.IsChess960(), c.fen)
+	}
+}
+
+func Test_TranslateFEN_Chess960KQkq(t *testing.T) {
+	fen := "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w KQkq - 2 9"
+
+	board := Board{}
+	assert.NotNil(t, board.TranslateFEN(fen))
+
+	board.SetChess960(true)
+	err := board.TranslateFEN(fen)
+
+	assert.Nil(t, err)
+	assert.True(t, board.IsChess960())
+	assert.Equal(t, fen, board.FEN())
+}
+
+func Test_TranslateFEN_StandardCastlingTypo(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/8/8/8/8/4K1R1 w K - 0 1")
+
+	assert.NotNil(t, err)
+	assert.False(t, board.IsChess960())
+}
+
+func Test_AvailableLegalMoves_Chess960Castles(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R2K1R1 w GBgb - 0 1")
+
+	moves := board.AvailableLegalMoves()
+
+	assert.Contains(t, moves, "e1b1")
+	assert.Contains(t, moves, "e1g1")
+	assert.NotContains(t, moves, "e1c1")
+}
+
+func Test_MakeMove_Chess960Castles(t *testing.T) {
+	cases := []struct {
+		fen      string
+		movement string
+		king     string
+		rook     string
+		empty    []string
+		san      string
+	}{
+		{"1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R2K1R1 w GBgb - 0 1", "e1b1", "c1", "d1", []string{"e1", "b1"}, "O-O-O"},
+		{"1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R2K1R1 w GBgb - 0 1", "e1g1", "g1", "f1", []string{"e1"}, "O-O"},
+		{"r5kr/8/8/8/8/8/8/R5KR w HAha - 0 1", "g1h1", "g1", "f1", []string{"h1"}, "O-O"},
+		{"r5kr/8/8/8/8/8/8/R5KR w HAha - 0 1", "g1a1", "c1", "d1", []string{"g1", "a1"}, "O-O-O"},
+		{"rk2r3/pppppppp/8/8/8/8/PPPPPPPP/RK2R3 w EAea - 0 1", "b1a1", "c1", "d1", []string{"b1", "a1"}, "O-O-O"},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+		fen := board.FEN()
+
+		m, err := board.ParseMove(c.movement)
+		assert.Nil(t, err, c.movement)
+		assert.True(t, m.IsCastle(), c.movement)
+		assert.Equal(t, c.san, board.SAN(m), c.movement)
+
+		board.MakeMove(c.movement)
+
+		assert.Equal(t, WKing, board.GetPieceAt(c.king), c.movement)
+		assert.Equal(t, WRook, board.GetPieceAt(c.rook), c.movement)
+		for _, s := range c.empty {
+			assert.Equal(t, Piece(""), board.GetPieceAt(s), c.movement)
+		}
+		assert.Equal(t, []string{c.movement}, board.MovesHistory)
+		assert.NotContains(t, board.AvailableCastles, "K")
+
+		board.UnmakeMove()
+		assert.Equal(t, fen, board.FEN(), c.movement)
+	}
+}
+
+func Test_ParseMove_Chess960KingTwoSquares(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("1k6/8/8/8/8/8/8/RK5R w HA - 0 1")
+	assert.Nil(t, err)
+
+	_, err = board.ParseMove("b1d1")
+	assert.EqualError(t, err, "b1d1 is not a legal move")
+
+	m, err := board.ParseMove("b1h1")
+	assert.Nil(t, err)
+	assert.True(t, m.IsCastle())
+}
+
+func Test_ParseSAN_Chess960Castles(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("rk2r3/pppppppp/8/8/8/8/PPPPPPPP/RK2R3 w EAea - 0 1")
+
+	m, err := board.ParseSAN("O-O-O")
+	assert.Nil(t, err)
+	assert.Equal(t, "b1a1", m.String())
+	board.Play(m)
+
+	m, err = board.ParseSAN("O-O")
+	assert.Nil(t, err)
+	assert.Equal(t, "b8e8", m.String())
+	board.Play(m)
+
+	assert.Equal(t, "r4rk1/pppppppp/8/8/8/8/PPPPPPPP/2KRR3 w - - 2 2", board.FEN())
+}
+
+func Test_AvailableCastles_Chess960XFEN(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("rr2k3/8/8/8/8/8/8/RR2K2R w HBb - 0 1")
+
+	board.MakeMove("h1h2")
+
+	assert.Equal(t, "Bb", board.AvailableCastles)
+
+	board.MakeMove("b8c8")
+
+	assert.Equal(t, "B", board.AvailableCastles)
+}
+
+func Test_SetChess960(t *testing.T) {
+	board := NewBoard()
+	board.TranslateFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+
+	board.SetChess960(true)
+
+	assert.True(t, board.IsChess960())
+	assert.Contains(t, board.AvailableLegalMoves(), "e1h1")
+	assert.Contains(t, board.AvailableLegalMoves(), "e1a1")
+	assert.NotContains(t, board.AvailableLegalMoves(), "e1g1")
+
+	board.SetChess960(false)
+
+	assert.Contains(t, board.AvailableLegalMoves(), "e1g1")
+	assert.NotContains(t, board.AvailableLegalMoves(), "e1h1")
+}
@@ -0,0 +1,141 @@
+package chess
+
+import (
+	"strings"
+)
+
+type crazyhouse struct{ standard }
+
+const crazyhouseFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[] w KQkq - 0 1"
+
+// pieces in hand in the order they are written in FEN
+var pocketOrder = [5]pieceType{queen, rook, bishop, knight, pawn}
+
+func (crazyhouse) Name() string {
+	return "Crazyhouse"
+}
+
+func (crazyhouse) StartingFEN() string {
+	return crazyhouseFEN
+}
+
+// pieces in hand can be dropped in any empty square, pawns can not be dropped in first or last rank
+func (crazyhouse) adjust(p *position, moves []Move) []Move {
+	us := p.side
+	empty := ^p.occupied()
+	for t := pawn; t < king; t++ {
+		if p.pockets[us][t] == 0 {
+			continue
+		}
+
+		targets := empty
+		if t == pawn {
+			targets &^= rank1 | rank8
+		}
+
+		for targets != 0 {
+			m := dropMove(makePiece(us, t), targets.pop())
+			if p.isLegal(m) {
+				moves = append(moves, m)
+			}
+		}
+	}
+
+	return moves
+}
+
+// captured pieces go to the pocket of the player who captures them,
+// promoted pieces go back as pawns
+func (crazyhouse) afterMove(p *position, m Move) {
+	promoted := p.promoted
+	if m.flags&flagCapture != 0 {
+		captured := m.to
+		if m.flags&flagEnPassant != 0 {
+			captured = newSquare(m.to.file(), m.from.rank())
+		}
+
+		t := m.captured.kind()
+		if p.promoted.has(captured) {
+			t = pawn
+		}
+
+		p.addToPocket(p.side.other(), t)
+		p.promoted &^= captured.bitboard()
+	}
+
+	if p.promoted.has(m.from) {
+		p.promoted ^= m.from.bitboard() | m.to.bitboard()
+	}
+
+	if m.promotion != noPiece {
+		p.promoted |= m.to.bitboard()
+	}
+
+	p.hash ^= promotedKey(promoted ^ p.promoted)
+}
+
+// any piece in hand can still give mate, only two lone kings with empty pockets are a draw
+func (crazyhouse) insufficientMaterial(p *position) bool {
+	return p.occupied() == p.bitboardOf(white, king)|p.bitboardOf(black, king) && p.pockets == [2][5]int{}
+}
+
+// pockets hold pieces of the opponent, so material is checked for both players together
+func (crazyhouse) validate(p *position) error {
+	return firstError(p.validateKings, p.validatePocketMaterial, p.validatePawns, p.validateTurn,
+		p.validateCastling, p.validateEnPassant)
+}
+
+// there can not be more pieces on board and in pockets than the 32 a game starts with
+func (p *position) validatePocketMaterial() error {
+	pieces := p.occupied().count()
+	for c := range p.pockets {
+		for _, count := range p.pockets[c] {
+			pieces += count
+		}
+	}
+
+	if pieces > 32 {
+		return newFENError(FENPocket, RuleTooManyPieces, "there are %d pieces on board and in pockets, more than a game has", pieces)
+	}
+
+	return nil
+}
+
+func (p *position) addToPocket(c color, t pieceType) {
+	p.pockets[c][t]++
+	p.hash ^= pocketKey(c, t, p.pockets[c][t])
+}
+
+func (p *position) takeFromPocket(c color, t pieceType) {
+	if p.pockets[c][t] == 0 {
+		return
+	}
+
+	p.hash ^= pocketKey(c, t, p.pockets[c][t])
+	p.pockets[c][t]--
+}
+
+// returns pockets as written in FEN like "QNPnp", white pieces first
+func (p *position) pocketField() string {
+	str := ""
+	for c := white; c <= black; c++ {
+		for _, t := range pocketOrder {
+			str += strings.Repeat(string(makePiece(c, t).symbol()), p.pockets[c][t])
+		}
+	}
+
+	return str
+}
+
+// Pocket returns Crazyhouse pieces in hand of turn color, which must be "w" or "b" like in FEN.
+func (board Board) Pocket(turn string) map[Piece]int {
+	c := colorOf(turn)
+	pocket := map[Piece]int{}
+	for t, count := range board.position.pockets[c] {
+		if count > 0 {
+			pocket[makePiece(c, pieceType(t)).symbol()] = count
+		}
+	}
+
+	return pocket
+}
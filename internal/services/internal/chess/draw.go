@@ -0,0 +1,136 @@
+package chess
+
+import (
+	"fmt"
+)
+
+type (
+	// Termination is the reason why a game is over
+	Termination string
+
+	// Outcome of a board position, Result is like PGN result tag:
+	// "1-0", "0-1", "1/2-1/2" or "*" if game is not over.
+	Outcome struct {
+		Result      string
+		Termination Termination
+	}
+)
+
+const (
+	NoTermination        Termination = ""
+	Checkmate            Termination = "checkmate"
+	Stalemate            Termination = "stalemate"
+	InsufficientMaterial Termination = "insufficient_material"
+	FivefoldRepetition   Termination = "fivefold_repetition"
+	SeventyFiveMoveRule  Termination = "seventy_five_move_rule"
+	ThreefoldRepetition  Termination = "threefold_repetition"
+	FiftyMoveRule        Termination = "fifty_move_rule"
+)
+
+const (
+	resultWhiteWins   = "1-0"
+	resultBlackWins   = "0-1"
+	resultDraw        = "1/2-1/2"
+	resultNotFinished = "*"
+
+	fiftyMoveRuleHalfMoves       = 100
+	seventyFiveMoveRuleHalfMoves = 150
+)
+
+// SetPreviousPositions records positions reached before the current one, as FENs from oldest to newest.
+// they are read with the rules of board variant, so it must be set before.
+// boards created from a FEN do not know the game history, so this is the way to detect repetitions on them.
+func (board *Board) SetPreviousPositions(fens ...string) error {
+	history := make([]uint64, 0, len(fens))
+	for _, fen := range fens {
+		p, err := board.parsePosition(fen)
+		if err != nil {
+			return fmt.Errorf("error calling board.parsePosition: %w", err)
+		}
+
+		history = append(history, p.hash)
+	}
+
+	board.history = history
+	return nil
+}
+
+// Repetitions returns how many times current position was reached in the game, current time included.
+func (board Board) Repetitions() int {
+	repetitions := 1
+	for _, hash := range board.history {
+		if hash == board.position.hash {
+			repetitions++
+		}
+	}
+
+	return repetitions
+}
+
+// returns true if current position was reached at least three times,
+// then the player to move can claim a draw.
+func (board Board) IsThreefoldRepetition() bool {
+	return board.Repetitions() >= 3
+}
+
+// returns true if current position was reached at least five times, the game is drawn.
+func (board Board) IsFivefoldRepetition() bool {
+	return board.Repetitions() >= 5
+}
+
+// returns true if there were fifty moves without captures or pawn moves,
+// then the player to move can claim a draw.
+func (board Board) IsFiftyMoveRule() bool {
+	return board.position.halfMoves >= fiftyMoveRuleHalfMoves
+}
+
+// returns true if there were seventy five moves without captures or pawn moves, the game is drawn.
+func (board Board) IsSeventyFiveMoveRule() bool {
+	return board.position.halfMoves >= seventyFiveMoveRuleHalfMoves
+}
+
+// returns true if neither player can win. in standard chess: lone kings, a single minor piece,
+// or only bishops standing on squares of the same color.
+func (board Board) IsInsufficientMaterial() bool {
+	return board.position.rules().insufficientMaterial(&board.position)
+}
+
+// Outcome returns the result of the game in current position and why it ended.
+// variant rules are checked first, then checkmate and stalemate and then draws.
+// claimable draws (threefold repetition and fifty move rule) are reported as finished games.
+func (board Board) Outcome() Outcome {
+	if o := board.position.rules().outcome(&board.position); o.Termination != NoTermination {
+		return o
+	}
+
+	if len(board.position.legalMoves()) == 0 {
+		if !board.position.inCheck() {
+			return Outcome{Result: resultDraw, Termination: Stalemate}
+		}
+
+		if board.position.side == white {
+			return Outcome{Result: resultBlackWins, Termination: Checkmate}
+		}
+
+		return Outcome{Result: resultWhiteWins, Termination: Checkmate}
+	}
+
+	draws := []struct {
+		isDraw      bool
+		termination Termination
+	}{
+		{board.IsInsufficientMaterial(), InsufficientMaterial},
+		{board.IsFivefoldRepetition(), FivefoldRepetition},
+		{board.IsSeventyFiveMoveRule(), SeventyFiveMoveRule},
+		{board.IsThreefoldRepetition(), ThreefoldRepetition},
+		{board.IsFiftyMoveRule(), FiftyMoveRule},
+	}
+
+	for _, d := range draws {
+		if d.isDraw {
+			return Outcome{Result: resultDraw, Termination: d.termination}
+		}
+	}
+
+	return Outcome{Result: resultNotFinished, Termination: NoTermination}
+}
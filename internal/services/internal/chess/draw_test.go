@@ -0,0 +1,127 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Repetitions_KnightsGoingBackAndForward(t *testing.T) {
+	assert := assert.New(t)
+	board := NewBoard()
+	shuffle := []string{"g1f3", "g8f6", "f3g1", "f6g8"}
+
+	for _, m := range shuffle {
+		board.MakeMove(m)
+	}
+	assert.Equal(2, board.Repetitions())
+	assert.False(board.IsThreefoldRepetition())
+
+	for _, m := range shuffle {
+		board.MakeMove(m)
+	}
+	assert.True(board.IsThreefoldRepetition())
+	assert.False(board.IsFivefoldRepetition())
+	assert.Equal(Outcome{Result: "1/2-1/2", Termination: ThreefoldRepetition}, board.Outcome())
+
+	for i := 0; i < 2; i++ {
+		for _, m := range shuffle {
+			board.MakeMove(m)
+		}
+	}
+	assert.True(board.IsFivefoldRepetition())
+	assert.Equal(Outcome{Result: "1/2-1/2", Termination: FivefoldRepetition}, board.Outcome())
+
+	board.UnmakeMove()
+	assert.Equal(4, board.Repetitions())
+}
+
+func Test_Repetitions_InPassantSquareWithoutCapture(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("4k3/8/8/8/4P3/8/8/4K3 b - e3 0 1")
+
+	err := board.SetPreviousPositions("4k3/8/8/8/4P3/8/8/4K3 b - - 0 1", "4k3/8/8/8/4P3/8/8/4K3 b - - 4 3")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, board.Repetitions())
+}
+
+func Test_Repetitions_InPassantSquareWithCapture(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("4k3/8/8/8/3pP3/8/8/4K3 b - e3 0 1")
+
+	err := board.SetPreviousPositions("4k3/8/8/8/3pP3/8/8/4K3 b - - 0 1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, board.Repetitions())
+}
+
+func Test_SetPreviousPositions_InvalidFEN(t *testing.T) {
+	board := NewBoard()
+
+	err := board.SetPreviousPositions("invalid-fen")
+
+	assert.EqualError(t, err, "error calling board.parsePosition: invalid FEN fields: expected 4 to 6 fields, got 1")
+}
+
+func Test_IsFiftyMoveRule(t *testing.T) {
+	assert := assert.New(t)
+	board := Board{}
+
+	board.TranslateFEN("4k3/8/8/8/8/8/8/R3K3 w - - 99 80")
+	assert.False(board.IsFiftyMoveRule())
+
+	board.MakeMove("a1a2")
+	assert.True(board.IsFiftyMoveRule())
+	assert.False(board.IsSeventyFiveMoveRule())
+	assert.Equal(Outcome{Result: "1/2-1/2", Termination: FiftyMoveRule}, board.Outcome())
+
+	board.TranslateFEN("4k3/8/8/8/8/8/8/R3K3 w - - 150 120")
+	assert.True(board.IsSeventyFiveMoveRule())
+	assert.Equal(Outcome{Result: "1/2-1/2", Termination: SeventyFiveMoveRule}, board.Outcome())
+}
+
+func Test_IsInsufficientMaterial(t *testing.T) {
+	cases := []struct {
+		fen          string
+		insufficient bool
+	}{
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", true},
+		{"4k3/8/8/8/8/8/8/4KN2 w - - 0 1", true},
+		{"4kb2/8/8/8/8/8/8/4K3 w - - 0 1", true},
+		{"4k1b1/8/8/8/8/8/8/3BK3 w - - 0 1", true},
+		{"4kb2/8/8/8/8/8/8/3BK3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/3NKN2 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/4KB1N w - - 0 1", false},
+		{"4k3/p7/8/8/8/8/8/4K3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/R3K3 w - - 0 1", false},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+
+		assert.Equal(t, c.insufficient, board.IsInsufficientMaterial(), c.fen)
+	}
+}
+
+func Test_Outcome(t *testing.T) {
+	cases := []struct {
+		fen     string
+		outcome Outcome
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", Outcome{Result: "*", Termination: NoTermination}},
+		{"k7/1Q6/2K5/8/8/8/8/8 b - - 0 1", Outcome{Result: "1-0", Termination: Checkmate}},
+		{"7k/8/8/8/8/8/5PPP/r5K1 w - - 0 1", Outcome{Result: "0-1", Termination: Checkmate}},
+		{"k7/1R6/2K5/8/8/8/8/8 b - - 0 1", Outcome{Result: "1/2-1/2", Termination: Stalemate}},
+		{"8/8/4k3/8/8/3K4/8/8 w - - 0 1", Outcome{Result: "1/2-1/2", Termination: InsufficientMaterial}},
+		{"k7/1Q6/2K5/8/8/8/8/8 b - - 150 90", Outcome{Result: "1-0", Termination: Checkmate}},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+
+		assert.Equal(t, c.outcome, board.Outcome(), c.fen)
+	}
+}
@@ -0,0 +1,192 @@
+package chess
+
+import (
+	"fmt"
+)
+
+type (
+	// FENField is the FEN field an error refers to
+	FENField string
+
+	// FENRule is the rule a FEN breaks
+	FENRule string
+
+	// FENError describes why a FEN is not a valid chess position
+	FENError struct {
+		Field  FENField
+		Rule   FENRule
+		Reason string
+	}
+)
+
+const (
+	FENFields         FENField = "fields"
+	FENPiecePlacement FENField = "piece_placement"
+	FENActiveColor    FENField = "active_color"
+	FENCastling       FENField = "castling"
+	FENEnPassant      FENField = "en_passant"
+	FENHalfMoveClock  FENField = "halfmove_clock"
+	FENFullMoveNumber FENField = "fullmove_number"
+
+	// Crazyhouse pieces in hand, like "[Qn]" after piece placement
+	FENPocket FENField = "pocket"
+
+	// Three-check field with remaining checks, like "3+3"
+	FENRemainingChecks FENField = "remaining_checks"
+)
+
+const (
+	RuleSyntax                FENRule = "syntax"
+	RuleKingCount             FENRule = "king_count"
+	RulePawnOnBackRank        FENRule = "pawn_on_back_rank"
+	RuleTooManyPieces         FENRule = "too_many_pieces"
+	RuleOpponentInCheck       FENRule = "opponent_in_check"
+	RuleCastlingWithoutPieces FENRule = "castling_without_pieces"
+	RuleImpossibleEnPassant   FENRule = "impossible_en_passant"
+)
+
+var colorNames = [2]string{"white", "black"}
+
+func newFENError(field FENField, rule FENRule, format string, args ...interface{}) FENError {
+	return FENError{Field: field, Rule: rule, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (e FENError) Error() string {
+	return fmt.Sprintf("invalid FEN %s: %s", e.Field, e.Reason)
+}
+
+// ValidateFEN checks that fen is well formed and describes a legal position.
+// returned error is a FENError telling which field breaks which rule.
+func ValidateFEN(fen string) error {
+	_, err := parsePosition(fen, false)
+	return err
+}
+
+// check that position could be reached in a game of its variant
+func (p *position) validate() error {
+	return p.rules().validate(p)
+}
+
+// returns the first error of checks, run in order
+func firstError(checks ...func() error) error {
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *position) validateKings() error {
+	for c := white; c <= black; c++ {
+		if kings := p.bitboardOf(c, king).count(); kings != 1 {
+			return newFENError(FENPiecePlacement, RuleKingCount, "there must be one %s king, got %d", colorNames[c], kings)
+		}
+	}
+
+	return nil
+}
+
+func (p *position) validatePawns() error {
+	if (p.bitboardOf(white, pawn)|p.bitboardOf(black, pawn))&(rank1|rank8) != 0 {
+		return newFENError(FENPiecePlacement, RulePawnOnBackRank, "pawns can not be on first or last rank")
+	}
+
+	return nil
+}
+
+// player who has just moved can not have its king in check
+func (p *position) validateTurn() error {
+	them := p.side.other()
+	flipped := *p
+	flipped.side = them
+	if flipped.inCheck() {
+		return newFENError(FENActiveColor, RuleOpponentInCheck, "%s king is in check but it is %s turn",
+			colorNames[them], colorNames[p.side])
+	}
+
+	return nil
+}
+
+func (p *position) validateCastling() error {
+	for i, s := range castlingSymbols {
+		if p.castling&(1<<i) == 0 {
+			continue
+		}
+
+		c := color(i / 2)
+		backRank := 7 * int(c)
+		king, rookSquare := p.kingSquare(c), p.castleRooks[i]
+		if p.chess960 && (king.rank() != backRank || p.squares[rookSquare] != makePiece(c, rook) ||
+			(rookSquare.file() > king.file()) != (i%2 == 0)) {
+			return newFENError(FENCastling, RuleCastlingWithoutPieces, "%s needs %s king in rank %d and rook in %s",
+				s, colorNames[c], backRank+1, rookSquare)
+		}
+
+		if !p.chess960 && (king != newSquare(4, backRank) || p.squares[rookSquare] != makePiece(c, rook)) {
+			return newFENError(FENCastling, RuleCastlingWithoutPieces, "%s needs %s king in %s and rook in %s",
+				s, colorNames[c], newSquare(4, backRank), rookSquare)
+		}
+	}
+
+	return nil
+}
+
+// check that every color has no more pieces than the ones it starts with plus its promoted pawns
+func (p *position) validateMaterial() error {
+	for c := white; c <= black; c++ {
+		if err := p.validateMaterialOf(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *position) validateMaterialOf(c color) error {
+	pawns := p.bitboardOf(c, pawn).count()
+	if pawns > 8 || p.colors[c].count() > 16 {
+		return newFENError(FENPiecePlacement, RuleTooManyPieces, "%s has more pieces than a side can have", colorNames[c])
+	}
+
+	promoted := 0
+	for t, initial := range map[pieceType]int{knight: 2, bishop: 2, rook: 2, queen: 1} {
+		if extra := p.bitboardOf(c, t).count() - initial; extra > 0 {
+			promoted += extra
+		}
+	}
+
+	if promoted > 8-pawns {
+		return newFENError(FENPiecePlacement, RuleTooManyPieces, "%s has %d promoted pieces but only %d missing pawns",
+			colorNames[c], promoted, 8-pawns)
+	}
+
+	return nil
+}
+
+// en passant square must be empty, just behind a pawn that has been pushed two squares
+func (p *position) validateEnPassant() error {
+	if p.epSquare == noSquare {
+		return nil
+	}
+
+	forward, rank := -8, 5
+	if p.side == black {
+		forward, rank = 8, 2
+	}
+
+	if p.epSquare.rank() != rank {
+		return newFENError(FENEnPassant, RuleImpossibleEnPassant, "%s is not in rank %d", p.epSquare, rank+1)
+	}
+
+	pushed := square(int(p.epSquare) + forward)
+	origin := square(int(p.epSquare) - forward)
+	if p.squares[pushed] != makePiece(p.side.other(), pawn) || p.squares[p.epSquare] != noPiece ||
+		p.squares[origin] != noPiece {
+		return newFENError(FENEnPassant, RuleImpossibleEnPassant, "there is no pawn that could have been pushed through %s",
+			p.epSquare)
+	}
+
+	return nil
+}
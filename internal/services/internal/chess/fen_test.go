@@ -0,0 +1,101 @@
+package chess
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateFEN_Valid(t *testing.T) {
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq -",
+		"4k3/8/8/8/8/8/8/4K3 w - - 5",
+		"QQQQk3/8/8/8/8/8/8/4K3 b - - 0 1",
+	}
+
+	for _, fen := range fens {
+		assert.Nil(t, ValidateFEN(fen), fen)
+	}
+}
+
+func Test_ValidateFEN_Invalid(t *testing.T) {
+	cases := []struct {
+		fen   string
+		field FENField
+		rule  FENRule
+		err   string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq", FENFields, RuleSyntax,
+			"invalid FEN fields: expected 4 to 6 fields, got 3"},
+		{"rnbqkbnr/pppppppp/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", FENPiecePlacement, RuleSyntax,
+			"invalid FEN piece_placement: expected 8 ranks, got 7"},
+		{"4k3/8/8/8/3N5/8/8/4K3 w - - 0 1", FENPiecePlacement, RuleSyntax,
+			"invalid FEN piece_placement: rank 4 has 9 squares instead of 8"},
+		{"4k3/8/8/8/3N3/8/8/4K3 w - - 0 1", FENPiecePlacement, RuleSyntax,
+			"invalid FEN piece_placement: rank 4 has 7 squares instead of 8"},
+		{"4k3/8/8/8/3X4/8/8/4K3 w - - 0 1", FENPiecePlacement, RuleSyntax,
+			"invalid FEN piece_placement: unknown piece 'X'"},
+		{"4k3/8/8/8/8/8/8/4K3 x - - 0 1", FENActiveColor, RuleSyntax,
+			`invalid FEN active_color: expected w or b, got "x"`},
+		{"4k3/8/8/8/8/8/8/4K3 w KK - 0 1", FENCastling, RuleSyntax,
+			`invalid FEN castling: castling field "KK" has more than one right for the same rook`},
+		{"4k3/8/8/8/8/8/8/4K3 w KX - 0 1", FENCastling, RuleSyntax,
+			`invalid FEN castling: expected - or castling rights like KQkq or HAha, got "KX"`},
+		{"4k3/8/8/8/8/8/8/4K3 w - e4 0 1", FENEnPassant, RuleSyntax,
+			`invalid FEN en_passant: expected - or a square of rank 3 or 6, got "e4"`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - -1 1", FENHalfMoveClock, RuleSyntax,
+			`invalid FEN halfmove_clock: expected a non negative number, got "-1"`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 0", FENFullMoveNumber, RuleSyntax,
+			`invalid FEN fullmove_number: expected a positive number, got "0"`},
+		{"8/8/8/8/8/8/8/4K3 w - - 0 1", FENPiecePlacement, RuleKingCount,
+			"invalid FEN piece_placement: there must be one black king, got 0"},
+		{"4k3/8/8/8/8/8/8/4KK2 w - - 0 1", FENPiecePlacement, RuleKingCount,
+			"invalid FEN piece_placement: there must be one white king, got 2"},
+		{"4k3/8/8/8/8/8/8/P3K3 w - - 0 1", FENPiecePlacement, RulePawnOnBackRank,
+			"invalid FEN piece_placement: pawns can not be on first or last rank"},
+		{"4k3/pppppppp/p7/8/8/8/8/4K3 w - - 0 1", FENPiecePlacement, RuleTooManyPieces,
+			"invalid FEN piece_placement: black has more pieces than a side can have"},
+		{"4k3/8/8/8/8/P7/PPPPPPP1/QQ2K3 w - - 0 1", FENPiecePlacement, RuleTooManyPieces,
+			"invalid FEN piece_placement: white has 1 promoted pieces but only 0 missing pawns"},
+		{"4k3/4R3/8/8/8/8/8/4K3 w - - 0 1", FENActiveColor, RuleOpponentInCheck,
+			"invalid FEN active_color: black king is in check but it is white turn"},
+		{"4k3/8/8/8/8/8/8/4K3 w K - 0 1", FENCastling, RuleCastlingWithoutPieces,
+			"invalid FEN castling: K needs white king in e1 and rook in h1"},
+		{"r3k3/8/8/8/8/8/8/4K2R w Qq - 0 1", FENCastling, RuleCastlingWithoutPieces,
+			"invalid FEN castling: Q needs white king in e1 and rook in a1"},
+		{"r3k3/8/8/8/8/8/3K4/R7 w Qq - 0 1", FENCastling, RuleCastlingWithoutPieces,
+			"invalid FEN castling: Q needs white king in e1 and rook in a1"},
+		{"4k3/8/8/8/8/8/8/4K1R1 w K - 0 1", FENCastling, RuleCastlingWithoutPieces,
+			"invalid FEN castling: K needs white king in e1 and rook in h1"},
+		{"r3k3/8/8/8/8/8/8/1K5R w A - 0 1", FENCastling, RuleCastlingWithoutPieces,
+			"invalid FEN castling: Q needs white king in rank 1 and rook in a1"},
+		{"4k3/8/8/8/4P3/8/8/4K3 w - e3 0 1", FENEnPassant, RuleImpossibleEnPassant,
+			"invalid FEN en_passant: e3 is not in rank 6"},
+		{"4k3/8/8/8/8/8/8/4K3 b - e3 0 1", FENEnPassant, RuleImpossibleEnPassant,
+			"invalid FEN en_passant: there is no pawn that could have been pushed through e3"},
+	}
+
+	for _, c := range cases {
+		err := ValidateFEN(c.fen)
+
+		fenErr := FENError{}
+		assert.True(t, errors.As(err, &fenErr), c.fen)
+		assert.Equal(t, c.field, fenErr.Field, c.fen)
+		assert.Equal(t, c.rule, fenErr.Rule, c.fen)
+		assert.EqualError(t, err, c.err, c.fen)
+	}
+}
+
+func Test_TranslateFEN_IllegalPositionDoesNotChangeBoard(t *testing.T) {
+	board := NewBoard()
+	fen := board.FEN()
+
+	err := board.TranslateFEN("4k3/4R3/8/8/8/8/8/4K3 w - - 0 1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, fen, board.FEN())
+	assert.Len(t, board.AvailableLegalMoves(), 20)
+}
@@ -0,0 +1,466 @@
+package chess
+
+import (
+	"strings"
+)
+
+type (
+	moveFlag uint8
+
+	// Move is a movement of the side to move in a Board position.
+	// use Board.LegalMoves, Board.ParseMove or Board.ParseSAN to get one.
+	Move struct {
+		from      square
+		to        square
+		piece     piece
+		captured  piece
+		promotion piece
+		flags     moveFlag
+	}
+)
+
+const (
+	flagCapture moveFlag = 1 << iota
+	flagDoublePush
+	flagEnPassant
+	flagCastle
+
+	// only set in moves returned by Board methods other than LegalMovesWithoutChecks,
+	// move generation does not calculate it
+	flagCheck
+
+	// castle of a Chess960 game, written in UCI as the king taking its own rook
+	flagChess960
+
+	// Crazyhouse piece put from a pocket in an empty square, origin and target are the same
+	flagDrop
+)
+
+// promotion pieces in the same order they are generated
+var promotionTypes = [4]pieceType{queen, rook, knight, bishop}
+
+// returns move as origin and target squares plus promotion piece,
+// like "e2e4" or "h7h8Q". Chess960 castles target the castling rook, like "b1a1",
+// and drops are written as piece letter and target square, like "N@f3".
+func (m Move) String() string {
+	if m.flags&flagDrop != 0 {
+		return strings.ToUpper(string(m.piece.symbol())) + "@" + m.to.String()
+	}
+
+	return m.from.String() + m.target().String() + string(m.promotion.symbol())
+}
+
+// castles are stored as the king taking its own rook, target is the square
+// the king goes to unless it is a Chess960 castle
+func (m Move) target() square {
+	if m.flags&flagCastle == 0 || m.flags&flagChess960 != 0 {
+		return m.to
+	}
+
+	return m.kingTo()
+}
+
+// returns true if castle is done with the rook of the king side (h file side)
+func (m Move) isKingSide() bool {
+	return m.to.file() > m.from.file()
+}
+
+// square the king ends in, it is target square for every move but castles
+func (m Move) kingTo() square {
+	if m.flags&flagCastle == 0 {
+		return m.to
+	}
+
+	if m.isKingSide() {
+		return newSquare(6, m.from.rank())
+	}
+
+	return newSquare(2, m.from.rank())
+}
+
+// square the castling rook ends in
+func (m Move) rookTo() square {
+	if m.isKingSide() {
+		return newSquare(5, m.from.rank())
+	}
+
+	return newSquare(3, m.from.rank())
+}
+
+// returns origin square like "e2", or an empty string if move is a drop
+func (m Move) From() string {
+	if m.flags&flagDrop != 0 {
+		return ""
+	}
+
+	return m.from.String()
+}
+
+// returns target square like "e4"
+func (m Move) To() string {
+	return m.target().String()
+}
+
+// returns moved piece
+func (m Move) Piece() Piece {
+	return m.piece.symbol()
+}
+
+// returns captured piece, or an empty Piece if move is not a capture
+func (m Move) Captured() Piece {
+	return m.captured.symbol()
+}
+
+// returns the piece a pawn is promoted to, or an empty Piece if move is not a promotion
+func (m Move) Promotion() Piece {
+	return m.promotion.symbol()
+}
+
+func (m Move) IsCapture() bool {
+	return m.flags&flagCapture != 0
+}
+
+func (m Move) IsCastle() bool {
+	return m.flags&flagCastle != 0
+}
+
+func (m Move) IsEnPassant() bool {
+	return m.flags&flagEnPassant != 0
+}
+
+func (m Move) IsDoublePush() bool {
+	return m.flags&flagDoublePush != 0
+}
+
+// returns true if move puts a piece from a Crazyhouse pocket
+func (m Move) IsDrop() bool {
+	return m.flags&flagDrop != 0
+}
+
+// returns true if move gives check to the opponent king
+func (m Move) IsCheck() bool {
+	return m.flags&flagCheck != 0
+}
+
+// translate a movement string like "e2e4", "e7e8q" or "N@f3" to a move of side to move.
+// castles can be written as the king moving two squares or taking its own rook.
+// flags are deduced from board but legality is not checked.
+func (p *position) parseMove(movement string) (Move, bool) {
+	if len(movement) != 4 && len(movement) != 5 {
+		return Move{}, false
+	}
+
+	if movement[1] == '@' {
+		return p.parseDrop(movement)
+	}
+
+	from, ok := parseSquare(movement[:2])
+	if !ok {
+		return Move{}, false
+	}
+
+	to, ok := parseSquare(movement[2:4])
+	if !ok {
+		return Move{}, false
+	}
+
+	moved := p.squares[from]
+	if moved == noPiece {
+		return Move{}, false
+	}
+
+	m := Move{from: from, to: to, piece: moved, captured: p.squares[to], promotion: noPiece}
+	if len(movement) == 5 {
+		promotion := pieceFromSymbol(Piece(strings.ToUpper(movement[4:])))
+		if promotion == noPiece {
+			return Move{}, false
+		}
+		m.promotion = makePiece(moved.color(), promotion.kind())
+	}
+
+	if m.captured != noPiece {
+		m.flags |= flagCapture
+	}
+
+	distance := int(to) - int(from)
+	switch moved.kind() {
+	case pawn:
+		if to == p.epSquare && to.file() != from.file() {
+			m.flags |= flagEnPassant | flagCapture
+			m.captured = makePiece(moved.color().other(), pawn)
+		}
+		if distance == 16 || distance == -16 {
+			m.flags |= flagDoublePush
+		}
+	case king:
+		if m.captured == makePiece(moved.color(), rook) {
+			return p.castleMove(m.from, m.to)
+		}
+
+		// standard castles can be written as the king moving two squares from its starting one,
+		// Chess960 ones are only written as the king taking its own rook
+		backRank := 0
+		if moved.color() == black {
+			backRank = 7
+		}
+		if !p.chess960 && (distance == 2 || distance == -2) && from == newSquare(4, backRank) && to.rank() == backRank {
+			return p.castleMove(m.from, p.castleRooks[castlingIndex(moved.color(), distance > 0)])
+		}
+	}
+
+	return m, true
+}
+
+// translate a drop like "N@f3" or "P@e4", piece letter case is ignored
+func (p *position) parseDrop(movement string) (Move, bool) {
+	dropped := pieceFromSymbol(Piece(strings.ToUpper(movement[:1])))
+	to, ok := parseSquare(movement[2:])
+	if dropped == noPiece || dropped.kind() == king || !ok || p.squares[to] != noPiece {
+		return Move{}, false
+	}
+
+	return dropMove(makePiece(p.side, dropped.kind()), to), true
+}
+
+func dropMove(dropped piece, to square) Move {
+	return Move{from: to, to: to, piece: dropped, captured: noPiece, promotion: noPiece, flags: flagDrop}
+}
+
+// give all legal moves for side to move, none if variant rules have ended the game
+func (p *position) legalMoves() []Move {
+	if p.variantEnded() {
+		return nil
+	}
+
+	return p.generateLegalMoves()
+}
+
+// tell if variant rules have ended the game, like on the third check in Three-check
+func (p *position) variantEnded() bool {
+	return p.variant != nil && p.variant.outcome(p).Termination != NoTermination
+}
+
+// give all moves for side to move that follow the rules, even if the game is over
+func (p *position) generateLegalMoves() []Move {
+	moves := p.pseudoLegalMoves(make([]Move, 0, 64))
+	legal := moves[:0]
+
+	// standard legality only depends on own king safety, pins tell it without making every move
+	if p.rules() == Standard {
+		pinned, checkers := p.pinned(p.side), p.checkers()
+		for _, m := range moves {
+			if p.isSafe(m, pinned, checkers) {
+				legal = append(legal, m)
+			}
+		}
+
+		return legal
+	}
+
+	for _, m := range moves {
+		if p.isLegal(m) {
+			legal = append(legal, m)
+		}
+	}
+
+	return p.rules().adjust(p, legal)
+}
+
+// returns true if m follows variant rules, for standard chess it must not leave own king under attack
+func (p *position) isLegal(m Move) bool {
+	after := *p
+	after.makeMove(m)
+	return p.rules().legal(p, m, &after)
+}
+
+// append to moves all possible moves (legal or not) for side to move.
+// castles are only generated if king does not cross attacked squares.
+func (p *position) pseudoLegalMoves(moves []Move) []Move {
+	us := p.side
+	own := p.colors[us]
+	occupied := p.occupied()
+
+	moves = p.pawnMoves(moves)
+
+	for t := knight; t <= king; t++ {
+		pieces := p.bitboardOf(us, t)
+		for pieces != 0 {
+			from := pieces.pop()
+			targets := p.attacksFrom(t, from, occupied) &^ own
+			moves = p.appendMoves(moves, from, targets)
+		}
+	}
+
+	return p.castleMoves(moves)
+}
+
+// attacks of a non pawn piece type from square s
+func (p *position) attacksFrom(t pieceType, s square, occupied bitboard) bitboard {
+	switch t {
+	case knight:
+		return knightAttacks[s]
+	case bishop:
+		return bishopAttacks(s, occupied)
+	case rook:
+		return rookAttacks(s, occupied)
+	case queen:
+		return queenAttacks(s, occupied)
+	case king:
+		return kingAttacks[s]
+	}
+
+	return 0
+}
+
+func (p *position) appendMoves(moves []Move, from square, targets bitboard) []Move {
+	for targets != 0 {
+		to := targets.pop()
+		m := Move{from: from, to: to, piece: p.squares[from], captured: p.squares[to], promotion: noPiece}
+		if m.captured != noPiece {
+			m.flags = flagCapture
+		}
+
+		moves = append(moves, m)
+	}
+
+	return moves
+}
+
+func (p *position) pawnMoves(moves []Move) []Move {
+	us := p.side
+	moved := makePiece(us, pawn)
+	pawns := p.pieces[moved]
+	empty := ^p.occupied()
+	enemies := p.colors[us.other()]
+
+	forward, startRank, lastRank := 8, rank2, rank8
+	if us == black {
+		forward, startRank, lastRank = -8, rank7, rank1
+	}
+
+	for pawns != 0 {
+		from := pawns.pop()
+		single := square(int(from) + forward)
+		if empty.has(single) {
+			moves = appendPawnMove(moves, Move{from: from, to: single, piece: moved, captured: noPiece}, us, lastRank)
+
+			double := square(int(single) + forward)
+			if startRank.has(from) && empty.has(double) {
+				moves = append(moves, Move{from: from, to: double, piece: moved, captured: noPiece,
+					promotion: noPiece, flags: flagDoublePush})
+			}
+		}
+
+		captures := pawnAttacks[us][from] & enemies
+		for captures != 0 {
+			to := captures.pop()
+			m := Move{from: from, to: to, piece: moved, captured: p.squares[to], flags: flagCapture}
+			moves = appendPawnMove(moves, m, us, lastRank)
+		}
+
+		if p.epSquare != noSquare && pawnAttacks[us][from].has(p.epSquare) {
+			moves = append(moves, Move{from: from, to: p.epSquare, piece: moved,
+				captured: makePiece(us.other(), pawn), promotion: noPiece, flags: flagCapture | flagEnPassant})
+		}
+	}
+
+	return moves
+}
+
+// appends m, or all its promotions if pawn reaches the last rank
+func appendPawnMove(moves []Move, m Move, us color, lastRank bitboard) []Move {
+	if !lastRank.has(m.to) {
+		m.promotion = noPiece
+		return append(moves, m)
+	}
+
+	for _, t := range promotionTypes {
+		m.promotion = makePiece(us, t)
+		moves = append(moves, m)
+	}
+
+	return moves
+}
+
+func (p *position) castleMoves(moves []Move) []Move {
+	us := p.side
+	rights := p.castling & castlingOf(us)
+	if rights == 0 {
+		return moves
+	}
+
+	kingFrom := p.kingSquare(us)
+	if kingFrom == noSquare || p.isAttacked(kingFrom, us.other()) {
+		return moves
+	}
+
+	for _, kingSide := range []bool{true, false} {
+		i := castlingIndex(us, kingSide)
+		if rights&(1<<i) == 0 {
+			continue
+		}
+
+		rookFrom := p.castleRooks[i]
+		if p.squares[rookFrom] != makePiece(us, rook) || rookFrom.rank() != kingFrom.rank() {
+			continue
+		}
+
+		kingTo, rookTo := newSquare(2, kingFrom.rank()), newSquare(3, kingFrom.rank())
+		if kingSide {
+			kingTo, rookTo = newSquare(6, kingFrom.rank()), newSquare(5, kingFrom.rank())
+		}
+
+		kingPath := rankSpan(kingFrom, kingTo)
+		mustBeEmpty := (kingPath | rankSpan(rookFrom, rookTo)) &^ (kingFrom.bitboard() | rookFrom.bitboard())
+		if mustBeEmpty&p.occupied() != 0 {
+			continue
+		}
+
+		attacked := false
+		for path := kingPath; path != 0; {
+			if p.isAttacked(path.pop(), us.other()) {
+				attacked = true
+				break
+			}
+		}
+
+		if !attacked {
+			m, _ := p.castleMove(kingFrom, rookFrom)
+			moves = append(moves, m)
+		}
+	}
+
+	return moves
+}
+
+// castle of king in kingFrom with rook in rookFrom, it returns false if rook
+// is not one of the castling rooks
+func (p *position) castleMove(kingFrom, rookFrom square) (Move, bool) {
+	m := Move{from: kingFrom, to: rookFrom, piece: p.squares[kingFrom], captured: noPiece,
+		promotion: noPiece, flags: flagCastle}
+	if p.chess960 {
+		m.flags |= flagChess960
+	}
+
+	for _, s := range p.castleRooks {
+		if s == rookFrom {
+			return m, true
+		}
+	}
+
+	return Move{}, false
+}
+
+// squares from a to b (both included), a and b must be in the same rank
+func rankSpan(a, b square) bitboard {
+	if a > b {
+		a, b = b, a
+	}
+
+	span := bitboard(0)
+	for s := a; s <= b; s++ {
+		span |= s.bitboard()
+	}
+
+	return span
+}
@@ -0,0 +1,44 @@
+package chess
+
+// Perft counts leaf nodes of the legal moves tree from board position up to depth.
+// it is used to check move generation against known node counts.
+func (board Board) Perft(depth int) int {
+	return board.position.perft(depth)
+}
+
+// Divide returns perft of depth - 1 after every legal move, keyed by movement.
+// sum of values is Perft(depth), it is used to find which move is wrong when perft fails.
+func (board Board) Divide(depth int) map[string]int {
+	nodes := map[string]int{}
+	if depth < 1 {
+		return nodes
+	}
+
+	for _, m := range board.position.legalMoves() {
+		after := board.position
+		after.makeMove(m)
+		nodes[m.String()] = after.perft(depth - 1)
+	}
+
+	return nodes
+}
+
+func (p *position) perft(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+
+	moves := p.legalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+
+	nodes := 0
+	for _, m := range moves {
+		after := *p
+		after.makeMove(m)
+		nodes += after.perft(depth - 1)
+	}
+
+	return nodes
+}
@@ -0,0 +1,165 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type perftCase struct {
+	name    string
+	fen     string
+	variant Variant
+	nodes   map[int]int
+}
+
+// node counts by depth from https://www.chessprogramming.org/Perft_Results
+var perftCases = []perftCase{
+	{
+		name:  "initial position",
+		fen:   "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		nodes: map[int]int{1: 20, 2: 400, 3: 8902, 4: 197281, 5: 4865609},
+	},
+	{
+		name:  "kiwipete",
+		fen:   "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		nodes: map[int]int{1: 48, 2: 2039, 3: 97862, 4: 4085603},
+	},
+	{
+		name:  "en passant and pins",
+		fen:   "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		nodes: map[int]int{1: 14, 2: 191, 3: 2812, 4: 43238, 5: 674624},
+	},
+	{
+		name:  "promotions and castles",
+		fen:   "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		nodes: map[int]int{1: 6, 2: 264, 3: 9467, 4: 422333},
+	},
+	{
+		name:  "promotions with check",
+		fen:   "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		nodes: map[int]int{1: 44, 2: 1486, 3: 62379, 4: 2103487},
+	},
+	{
+		name:  "middlegame",
+		fen:   "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+		nodes: map[int]int{1: 46, 2: 2079, 3: 89890, 4: 3894594},
+	},
+	{
+		name:  "illegal en passant capture",
+		fen:   "3k4/3p4/8/K1P4r/8/8/8/8 b - - 0 1",
+		nodes: map[int]int{1: 18, 2: 92, 3: 1670, 4: 10138, 5: 185429, 6: 1134888},
+	},
+	{
+		name:  "en passant capture gives check",
+		fen:   "8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1",
+		nodes: map[int]int{6: 1440467},
+	},
+	{
+		name:  "short castling gives check",
+		fen:   "5k2/8/8/8/8/8/8/4K2R w K - 0 1",
+		nodes: map[int]int{6: 661072},
+	},
+	{
+		name:  "castling with rooks attacked",
+		fen:   "r3k2r/8/3Q4/8/8/5q2/8/R3K2R b KQkq - 0 1",
+		nodes: map[int]int{1: 44, 2: 1494, 3: 50509, 4: 1720476},
+	},
+	{
+		name:  "promote out of check",
+		fen:   "2K2r2/4P3/8/8/8/8/8/3k4 w - - 0 1",
+		nodes: map[int]int{1: 11, 2: 133, 3: 1442, 4: 19174, 5: 266199},
+	},
+	{
+		name:  "underpromote to check",
+		fen:   "8/P1k5/K7/8/8/8/8/8 w - - 0 1",
+		nodes: map[int]int{1: 6, 2: 27, 3: 273, 4: 1329, 5: 18135, 6: 92683},
+	},
+	{
+		name:  "self stalemate",
+		fen:   "K1k5/8/P7/8/8/8/8/8 w - - 0 1",
+		nodes: map[int]int{1: 2, 2: 6, 3: 13, 4: 63, 5: 382, 6: 2217},
+	},
+	{
+		name:  "chess960 position 1",
+		fen:   "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9",
+		nodes: map[int]int{1: 21, 2: 528, 3: 12189, 4: 326672, 5: 8146062},
+	},
+	{
+		name:  "chess960 position 2",
+		fen:   "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9",
+		nodes: map[int]int{1: 21, 2: 807, 3: 18002, 4: 667366},
+	},
+	{
+		name:    "atomic initial position",
+		fen:     standardFEN,
+		variant: Atomic,
+		nodes:   map[int]int{1: 20, 2: 400, 3: 8902, 4: 197326},
+	},
+	{
+		name:    "antichess initial position",
+		fen:     antichessFEN,
+		variant: Antichess,
+		nodes:   map[int]int{1: 20, 2: 400, 3: 8067, 4: 153299},
+	},
+	{
+		name:    "crazyhouse initial position",
+		fen:     crazyhouseFEN,
+		variant: Crazyhouse,
+		nodes:   map[int]int{1: 20, 2: 400, 3: 8902, 4: 197281, 5: 4888832},
+	},
+}
+
+func Test_Perft(t *testing.T) {
+	for _, c := range perftCases {
+		t.Run(c.name, func(t *testing.T) {
+			board := Board{}
+			board.SetVariant(c.variant)
+			err := board.TranslateFEN(c.fen)
+			assert.Nil(t, err)
+
+			for depth, nodes := range c.nodes {
+				if testing.Short() && nodes > 100000 {
+					continue
+				}
+
+				assert.Equal(t, nodes, board.Perft(depth), "depth %d", depth)
+			}
+		})
+	}
+}
+
+func Test_Divide(t *testing.T) {
+	board := NewBoard()
+
+	divide := board.Divide(3)
+
+	assert.Len(t, divide, 20)
+	assert.Equal(t, 600, divide["e2e4"])
+	assert.Equal(t, 440, divide["g1f3"])
+
+	total := 0
+	for _, nodes := range divide {
+		total += nodes
+	}
+	assert.Equal(t, board.Perft(3), total)
+}
+
+func Test_Perft_DoesNotChangeBoard(t *testing.T) {
+	board := NewBoard()
+	fen := board.FEN()
+
+	board.Perft(3)
+	board.Divide(2)
+
+	assert.Equal(t, fen, board.FEN())
+	assert.Len(t, board.MovesHistory, 0)
+}
+
+func Benchmark_Perft(b *testing.B) {
+	board := NewBoard()
+
+	for i := 0; i < b.N; i++ {
+		board.Perft(4)
+	}
+}
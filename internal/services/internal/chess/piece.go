@@ -41,3 +41,82 @@ func (p Piece) IsColor(color string) bool {
 	c := rune(string(p)[0])
 	return (c > 'A' && c < 'Z' && color == "w") || (c > 'a' && c < 'z' && color == "b")
 }
+
+type (
+	color     int8
+	pieceType int8
+
+	// piece is the internal representation of a Piece used by bitboards,
+	// it is color * 6 + pieceType or noPiece for empty squares.
+	piece int8
+)
+
+const (
+	white color = iota
+	black
+)
+
+const (
+	pawn pieceType = iota
+	knight
+	bishop
+	rook
+	queen
+	king
+	noPieceType pieceType = -1
+)
+
+const noPiece piece = -1
+
+var pieceSymbols = [12]Piece{WPawn, WKnight, WBishop, WRook, WQueen, WKing,
+	BPawn, BKnight, BBishop, BRook, BQueen, BKing}
+
+func makePiece(c color, t pieceType) piece {
+	return piece(int(c)*6 + int(t))
+}
+
+func (p piece) color() color {
+	return color(p / 6)
+}
+
+// returns piece type, or noPieceType if p is noPiece
+func (p piece) kind() pieceType {
+	if p == noPiece {
+		return noPieceType
+	}
+
+	return pieceType(p % 6)
+}
+
+// returns FEN symbol of piece, or an empty Piece if p is noPiece
+func (p piece) symbol() Piece {
+	if p == noPiece {
+		return ""
+	}
+
+	return pieceSymbols[p]
+}
+
+// returns internal piece of a FEN symbol like "N" or "q", noPiece if symbol is unknown
+func pieceFromSymbol(symbol Piece) piece {
+	for i, s := range pieceSymbols {
+		if s == symbol {
+			return piece(i)
+		}
+	}
+
+	return noPiece
+}
+
+func (c color) other() color {
+	return c ^ 1
+}
+
+// returns color as FEN turn, "w" or "b"
+func (c color) String() string {
+	if c == black {
+		return "b"
+	}
+
+	return "w"
+}
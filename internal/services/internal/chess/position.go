@@ -0,0 +1,470 @@
+package chess
+
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	castlingRights uint8
+
+	// position is the bitboard representation of a board,
+	// it is the one used to generate and make moves.
+	position struct {
+		pieces    [12]bitboard
+		colors    [2]bitboard
+		squares   [64]piece
+		side      color
+		castling  castlingRights
+		epSquare  square
+		halfMoves int
+		fullMoves int
+		hash      uint64
+
+		// rook origin square for every castling right, indexed like castlingRights bits
+		castleRooks [4]square
+
+		// castles are written in UCI as king takes rook and in FEN with X-FEN rules
+		chess960 bool
+
+		// rules position is played with, nil for standard chess
+		variant Variant
+
+		// checks given by every color, only counted by variants that need them
+		checks [2]int
+
+		// Crazyhouse pieces in hand of every color, indexed by piece type
+		pockets [2][5]int
+
+		// Crazyhouse pieces that were pawns, they go back to a pocket as pawns when captured
+		promoted bitboard
+	}
+)
+
+const (
+	whiteKingSide castlingRights = 1 << iota
+	whiteQueenSide
+	blackKingSide
+	blackQueenSide
+)
+
+var castlingSymbols = [4]string{"K", "Q", "k", "q"}
+
+func newPosition() position {
+	p := position{epSquare: noSquare, fullMoves: 1}
+	for i := range p.squares {
+		p.squares[i] = noPiece
+	}
+
+	p.castleRooks = [4]square{newSquare(7, 0), newSquare(0, 0), newSquare(7, 7), newSquare(0, 7)}
+	return p
+}
+
+// parse a FEN string to a legal position, with Chess960 castling rules if chess960 is true.
+// halfmove clock and fullmove number are optional.
+// returned errors are FENError.
+func parsePosition(fen string, chess960 bool) (position, error) {
+	p, err := parseFEN(fen, chess960)
+	if err != nil {
+		return p, err
+	}
+
+	return p, p.validate()
+}
+
+// parse a FEN string to a position checking only FEN syntax,
+// the position could be illegal, e.g. without kings.
+// castling rights follow Chess960 rules if chess960 is true or the FEN has rook files.
+func parseFEN(fen string, chess960 bool) (position, error) {
+	p := newPosition()
+	p.chess960 = chess960
+	fields := strings.Fields(fen)
+
+	// Three-check FENs have remaining checks like "3+3" after en passant field
+	checksField := ""
+	if len(fields) > 4 && strings.Contains(fields[4], "+") {
+		checksField = fields[4]
+		fields = append(fields[:4:4], fields[5:]...)
+	}
+
+	if len(fields) < 4 || len(fields) > 6 {
+		return p, newFENError(FENFields, RuleSyntax, "expected 4 to 6 fields, got %d", len(fields))
+	}
+
+	// Crazyhouse pockets are written after pieces like "[Qn]", or as a ninth rank
+	placement, pocket := fields[0], ""
+	if i := strings.Index(placement, "["); i >= 0 && strings.HasSuffix(placement, "]") {
+		placement, pocket = placement[:i], placement[i+1:len(placement)-1]
+	}
+
+	rows := strings.Split(placement, "/")
+	if len(rows) == 9 {
+		rows, pocket = rows[:8], rows[8]
+	}
+
+	if len(rows) != 8 {
+		return p, newFENError(FENPiecePlacement, RuleSyntax, "expected 8 ranks, got %d", len(rows))
+	}
+
+	for i, row := range rows {
+		rank := 7 - i
+		file := 0
+		for _, c := range row {
+			// promoted pieces of Crazyhouse are followed by a tilde like "Q~"
+			if c == '~' {
+				if file == 0 || p.squares[newSquare(file-1, rank)] == noPiece {
+					return p, newFENError(FENPiecePlacement, RuleSyntax, "~ must follow a piece")
+				}
+				p.promoted |= newSquare(file-1, rank).bitboard()
+				continue
+			}
+
+			if file > 7 {
+				return p, newFENError(FENPiecePlacement, RuleSyntax, "rank %d has more than 8 squares", rank+1)
+			}
+
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+
+			pc := pieceFromSymbol(Piece(c))
+			if pc == noPiece {
+				return p, newFENError(FENPiecePlacement, RuleSyntax, "unknown piece %q", c)
+			}
+
+			p.put(pc, newSquare(file, rank))
+			file++
+		}
+
+		if file != 8 {
+			return p, newFENError(FENPiecePlacement, RuleSyntax, "rank %d has %d squares instead of 8", rank+1, file)
+		}
+	}
+
+	for _, c := range pocket {
+		pc := pieceFromSymbol(Piece(c))
+		if pc == noPiece || pc.kind() == king {
+			return p, newFENError(FENPocket, RuleSyntax, "unknown piece %q in pocket", c)
+		}
+		p.pockets[pc.color()][pc.kind()]++
+	}
+
+	switch fields[1] {
+	case "w":
+		p.side = white
+	case "b":
+		p.side = black
+	default:
+		return p, newFENError(FENActiveColor, RuleSyntax, "expected w or b, got %q", fields[1])
+	}
+
+	if err := p.parseCastling(fields[2]); err != nil {
+		return p, err
+	}
+
+	if fields[3] != "-" {
+		ep, ok := parseSquare(fields[3])
+		if !ok || ep.rank() != 2 && ep.rank() != 5 {
+			return p, newFENError(FENEnPassant, RuleSyntax, "expected - or a square of rank 3 or 6, got %q", fields[3])
+		}
+		p.epSquare = ep
+	}
+
+	if checksField != "" {
+		if err := p.parseRemainingChecks(checksField); err != nil {
+			return p, err
+		}
+	}
+
+	if len(fields) > 4 {
+		halfMoves, err := strconv.Atoi(fields[4])
+		if err != nil || halfMoves < 0 {
+			return p, newFENError(FENHalfMoveClock, RuleSyntax, "expected a non negative number, got %q", fields[4])
+		}
+		p.halfMoves = halfMoves
+	}
+
+	if len(fields) > 5 {
+		fullMoves, err := strconv.Atoi(fields[5])
+		if err != nil || fullMoves < 1 {
+			return p, newFENError(FENFullMoveNumber, RuleSyntax, "expected a positive number, got %q", fields[5])
+		}
+		p.fullMoves = fullMoves
+	}
+
+	p.hash = p.computeHash()
+	return p, nil
+}
+
+// parse castling field of a FEN after pieces are placed. Besides KQkq it accepts
+// rook files like Shredder-FEN and X-FEN do for Chess960 ("HAha", "Kb"), which mark
+// position as Chess960. KQkq are the outermost rooks in Chess960, and the corner ones otherwise.
+func (p *position) parseCastling(field string) error {
+	if field == "-" {
+		return nil
+	}
+
+	if strings.ContainsAny(strings.ToLower(field), "abcdefgh") {
+		p.chess960 = true
+	}
+
+	for _, r := range field {
+		c := white
+		if r >= 'a' && r <= 'z' {
+			c = black
+		}
+
+		backRank := 7 * int(c)
+		king := p.kingSquare(c)
+		rookSquare := noSquare
+		kingSide := true
+
+		switch lower := r | 0x20; {
+		case lower == 'k' || lower == 'q':
+			kingSide = lower == 'k'
+			if p.chess960 {
+				rookSquare = p.outermostRook(c, kingSide)
+			}
+			if rookSquare == noSquare {
+				// validation reports the missing pieces with the standard squares
+				rookSquare = newSquare(0, backRank)
+				if kingSide {
+					rookSquare = newSquare(7, backRank)
+				}
+			}
+		case lower >= 'a' && lower <= 'h':
+			rookSquare = newSquare(int(lower-'a'), backRank)
+			kingSide = king == noSquare || rookSquare.file() > king.file()
+		default:
+			return newFENError(FENCastling, RuleSyntax, "expected - or castling rights like KQkq or HAha, got %q", field)
+		}
+
+		i := castlingIndex(c, kingSide)
+		if p.castling&(1<<i) != 0 {
+			return newFENError(FENCastling, RuleSyntax, "castling field %q has more than one right for the same rook", field)
+		}
+
+		p.castling |= 1 << i
+		p.castleRooks[i] = rookSquare
+	}
+
+	return nil
+}
+
+// parse remaining checks of every player like "3+2"
+func (p *position) parseRemainingChecks(field string) error {
+	remaining := strings.Split(field, "+")
+	if len(remaining) != 2 {
+		return newFENError(FENRemainingChecks, RuleSyntax, "expected remaining checks like 3+3, got %q", field)
+	}
+
+	for c, r := range remaining {
+		checks, err := strconv.Atoi(r)
+		if err != nil || checks < 0 || checks > checksToWin {
+			return newFENError(FENRemainingChecks, RuleSyntax, "expected remaining checks like 3+3, got %q", field)
+		}
+		p.checks[c] = checksToWin - checks
+	}
+
+	return nil
+}
+
+func (p *position) put(pc piece, s square) {
+	b := s.bitboard()
+	p.pieces[pc] |= b
+	p.colors[pc.color()] |= b
+	p.squares[s] = pc
+	p.hash ^= pieceKey(pc, s)
+}
+
+func (p *position) remove(s square) {
+	pc := p.squares[s]
+	if pc == noPiece {
+		return
+	}
+
+	b := s.bitboard()
+	p.pieces[pc] &^= b
+	p.colors[pc.color()] &^= b
+	p.squares[s] = noPiece
+	p.hash ^= pieceKey(pc, s)
+}
+
+func (p *position) occupied() bitboard {
+	return p.colors[white] | p.colors[black]
+}
+
+func (p *position) bitboardOf(c color, t pieceType) bitboard {
+	return p.pieces[makePiece(c, t)]
+}
+
+// returns king square of color c, or noSquare if there is no king in board
+func (p *position) kingSquare(c color) square {
+	k := p.bitboardOf(c, king)
+	if k == 0 {
+		return noSquare
+	}
+
+	return k.first()
+}
+
+// all pieces of both colors attacking square s with the given occupancy
+func (p *position) attackersTo(s square, occupied bitboard) bitboard {
+	bishops := p.bitboardOf(white, bishop) | p.bitboardOf(black, bishop) |
+		p.bitboardOf(white, queen) | p.bitboardOf(black, queen)
+	rooks := p.bitboardOf(white, rook) | p.bitboardOf(black, rook) |
+		p.bitboardOf(white, queen) | p.bitboardOf(black, queen)
+
+	return pawnAttacks[black][s]&p.bitboardOf(white, pawn) |
+		pawnAttacks[white][s]&p.bitboardOf(black, pawn) |
+		knightAttacks[s]&(p.bitboardOf(white, knight)|p.bitboardOf(black, knight)) |
+		kingAttacks[s]&(p.bitboardOf(white, king)|p.bitboardOf(black, king)) |
+		bishopAttacks(s, occupied)&bishops |
+		rookAttacks(s, occupied)&rooks
+}
+
+func (p *position) isAttacked(s square, by color) bool {
+	return p.attackersTo(s, p.occupied())&p.colors[by] != 0
+}
+
+// returns true if side to move is in check following its variant rules
+func (p *position) inCheck() bool {
+	return p.rules().inCheck(p)
+}
+
+// returns true if king of color c is attacked
+func (p *position) kingAttacked(c color) bool {
+	k := p.kingSquare(c)
+	return k != noSquare && p.isAttacked(k, c.other())
+}
+
+// apply m to position without checking if it is legal
+func (p *position) makeMove(m Move) {
+	us := p.side
+	moved := p.squares[m.from]
+	if m.flags&flagDrop != 0 {
+		moved = m.piece
+	}
+	p.hash ^= castlingKey(p.castling) ^ p.inPassantKey() ^ p.turnKey()
+
+	p.halfMoves++
+	if moved.kind() == pawn || m.flags&flagCapture != 0 {
+		p.halfMoves = 0
+	}
+
+	switch {
+	case m.flags&flagDrop != 0:
+		p.takeFromPocket(us, moved.kind())
+		p.put(moved, m.to)
+	case m.flags&flagCastle != 0:
+		p.remove(m.from)
+		p.remove(m.to)
+		p.put(makePiece(us, king), m.kingTo())
+		p.put(makePiece(us, rook), m.rookTo())
+	case m.flags&flagEnPassant != 0:
+		p.remove(newSquare(m.to.file(), m.from.rank()))
+		p.remove(m.from)
+		p.put(moved, m.to)
+	default:
+		if m.promotion != noPiece {
+			moved = m.promotion
+		}
+
+		p.remove(m.to)
+		p.remove(m.from)
+		p.put(moved, m.to)
+	}
+
+	p.epSquare = noSquare
+	if m.flags&flagDoublePush != 0 {
+		p.epSquare = newSquare(m.from.file(), (m.from.rank()+m.to.rank())/2)
+	}
+
+	if moved.kind() == king {
+		p.castling &^= castlingOf(us)
+	}
+	for i, s := range p.castleRooks {
+		if s == m.from || s == m.to {
+			p.castling &^= 1 << i
+		}
+	}
+
+	if us == black {
+		p.fullMoves++
+	}
+	p.side = us.other()
+	p.hash ^= castlingKey(p.castling) ^ p.inPassantKey() ^ p.turnKey()
+
+	if p.variant != nil {
+		p.variant.afterMove(p, m)
+	}
+}
+
+// index of a castling right in castlingRights bits
+func castlingIndex(c color, kingSide bool) int {
+	i := int(c) * 2
+	if !kingSide {
+		i++
+	}
+
+	return i
+}
+
+// both castling rights of color c
+func castlingOf(c color) castlingRights {
+	if c == black {
+		return blackKingSide | blackQueenSide
+	}
+
+	return whiteKingSide | whiteQueenSide
+}
+
+// returns castling field of FEN like "KQkq", or an empty string if there are no rights.
+// in Chess960 positions rooks that are not the outermost of their side are written
+// with their file letter, following X-FEN.
+func (p *position) castlingField() string {
+	str := ""
+	for i, s := range castlingSymbols {
+		if p.castling&(1<<i) == 0 {
+			continue
+		}
+
+		rook := p.castleRooks[i]
+		if p.outermostRook(color(i/2), i%2 == 0) == rook {
+			str += s
+			continue
+		}
+
+		file := columnLetter[rook.file()]
+		if i < 2 {
+			file = strings.ToUpper(file)
+		}
+		str += file
+	}
+
+	return str
+}
+
+// returns the rook of color c in its first rank closest to the board edge of
+// the king or queen side of the king, or noSquare if there is not any
+func (p *position) outermostRook(c color, kingSide bool) square {
+	king := p.kingSquare(c)
+	backRank := 7 * int(c)
+	if king == noSquare || king.rank() != backRank {
+		return noSquare
+	}
+
+	start, end, step := 0, king.file(), 1
+	if kingSide {
+		start, end, step = 7, king.file(), -1
+	}
+
+	for file := start; file != end; file += step {
+		if p.squares[newSquare(file, backRank)] == makePiece(c, rook) {
+			return newSquare(file, backRank)
+		}
+	}
+
+	return noSquare
+}
@@ -0,0 +1,219 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+var sanPieceLetters = map[pieceType]string{knight: "N", bishop: "B", rook: "R", queen: "Q", king: "K"}
+
+// SAN returns move in Standard Algebraic Notation like "Nbd7", "exd6", "e8=Q+", "O-O-O#"
+// or "N@f3" for Crazyhouse drops. m must be a legal move of board position.
+func (board Board) SAN(m Move) string {
+	p := &board.position
+	san := ""
+	moved := p.squares[m.from]
+
+	switch {
+	case m.flags&flagDrop != 0:
+		san = m.String()
+	case m.flags&flagCastle != 0:
+		san = "O-O-O"
+		if m.isKingSide() {
+			san = "O-O"
+		}
+	case moved.kind() == pawn:
+		if m.flags&flagCapture != 0 {
+			san = m.from.String()[:1] + "x"
+		}
+
+		san += m.to.String()
+		if m.promotion != noPiece {
+			san += "=" + sanPieceLetters[m.promotion.kind()]
+		}
+	default:
+		san = sanPieceLetters[moved.kind()] + p.disambiguation(m)
+		if m.flags&flagCapture != 0 {
+			san += "x"
+		}
+
+		san += m.to.String()
+	}
+
+	after := *p
+	after.makeMove(m)
+	if after.inCheck() {
+		// a check that ends the game by variant rules is not a mate, even if no move is left
+		if !after.variantEnded() && len(after.generateLegalMoves()) == 0 {
+			return san + "#"
+		}
+
+		return san + "+"
+	}
+
+	return san
+}
+
+// ParseSAN translates a move in Standard Algebraic Notation to a legal move of board position.
+// check, mate and annotation suffixes ("+", "#", "!", "?") are ignored, castles
+// can be written with letter O or zero, promotion equal sign is optional and
+// pawn drops can be written without piece letter, like "@e4".
+func (board Board) ParseSAN(san string) (Move, error) {
+	s := strings.TrimRight(san, "+#!?")
+	legalMoves := board.position.legalMoves()
+
+	if s == "O-O" || s == "0-0" || s == "O-O-O" || s == "0-0-0" {
+		kingSide := len(s) == 3
+		for _, m := range legalMoves {
+			if m.flags&flagCastle != 0 && m.isKingSide() == kingSide {
+				return board.withCheck(m), nil
+			}
+		}
+
+		return Move{}, fmt.Errorf("%s is not a legal move", san)
+	}
+
+	if strings.Contains(s, "@") {
+		if strings.HasPrefix(s, "@") {
+			s = "P" + s
+		}
+
+		drop, ok := board.position.parseDrop(s)
+		if !ok {
+			return Move{}, fmt.Errorf("invalid SAN %q", san)
+		}
+
+		for _, m := range legalMoves {
+			if m == drop {
+				return board.withCheck(m), nil
+			}
+		}
+
+		return Move{}, fmt.Errorf("%s is not a legal move", san)
+	}
+
+	t, from, to, promotion, ok := decomposeSAN(s)
+	if !ok {
+		return Move{}, fmt.Errorf("invalid SAN %q", san)
+	}
+
+	var found []Move
+	for _, m := range legalMoves {
+		moved := board.position.squares[m.from]
+		if moved.kind() != t || m.to != to || m.flags&flagCastle != 0 {
+			continue
+		}
+
+		if from.file >= 0 && m.from.file() != from.file || from.rank >= 0 && m.from.rank() != from.rank {
+			continue
+		}
+
+		// pawn moves without origin file are pushes, captures always have it
+		if t == pawn && from.file < 0 && m.from.file() != to.file() {
+			continue
+		}
+
+		if m.promotion.kind() != promotion {
+			continue
+		}
+
+		found = append(found, m)
+	}
+
+	if len(found) == 0 {
+		return Move{}, fmt.Errorf("%s is not a legal move", san)
+	}
+
+	if len(found) > 1 {
+		return Move{}, fmt.Errorf("%s is ambiguous", san)
+	}
+
+	return board.withCheck(found[0]), nil
+}
+
+type sanOrigin struct {
+	file int
+	rank int
+}
+
+// split a SAN without suffixes like "Nbxd7" or "e8=Q" in its parts.
+// origin file and rank are -1 when SAN does not have them.
+func decomposeSAN(s string) (pieceType, sanOrigin, square, pieceType, bool) {
+	t, promotion := pawn, noPieceType
+	from := sanOrigin{file: -1, rank: -1}
+
+	if len(s) > 0 && strings.Contains("NBRQK", s[:1]) {
+		t = pieceTypeOfLetter(s[:1])
+		s = s[1:]
+	}
+
+	if t == pawn && len(s) > 2 {
+		last := strings.ToUpper(s[len(s)-1:])
+		// Antichess pawns can be promoted to king
+		if strings.Contains("NBRQK", last) {
+			promotion = pieceTypeOfLetter(last)
+			s = strings.TrimSuffix(s[:len(s)-1], "=")
+		}
+	}
+
+	if len(s) < 2 {
+		return t, from, noSquare, promotion, false
+	}
+
+	to, ok := parseSquare(s[len(s)-2:])
+	if !ok {
+		return t, from, noSquare, promotion, false
+	}
+
+	origin := strings.TrimSuffix(s[:len(s)-2], "x")
+	for _, c := range origin {
+		switch {
+		case c >= 'a' && c <= 'h' && from.file < 0:
+			from.file = int(c - 'a')
+		case c >= '1' && c <= '8' && from.rank < 0:
+			from.rank = int(c - '1')
+		default:
+			return t, from, noSquare, promotion, false
+		}
+	}
+
+	return t, from, to, promotion, true
+}
+
+func pieceTypeOfLetter(letter string) pieceType {
+	for t, l := range sanPieceLetters {
+		if l == letter {
+			return t
+		}
+	}
+
+	return noPieceType
+}
+
+// origin file, rank or square needed to tell m apart from other moves
+// of the same piece type to the same square
+func (p *position) disambiguation(m Move) string {
+	moved := p.squares[m.from]
+	sameFile, sameRank, others := false, false, false
+
+	for _, other := range p.legalMoves() {
+		if other.to != m.to || other.from == m.from || p.squares[other.from] != moved {
+			continue
+		}
+
+		others = true
+		sameFile = sameFile || other.from.file() == m.from.file()
+		sameRank = sameRank || other.from.rank() == m.from.rank()
+	}
+
+	switch {
+	case !others:
+		return ""
+	case !sameFile:
+		return m.from.String()[:1]
+	case !sameRank:
+		return m.from.String()[1:]
+	}
+
+	return m.from.String()
+}
@@ -0,0 +1,134 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SAN(t *testing.T) {
+	cases := []struct {
+		fen      string
+		movement string
+		san      string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e2e4", "e4"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "g1f3", "Nf3"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "e4d5", "exd5"},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 2", "e5d6", "exd6"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8c8", "O-O-O"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8Q", "b8=Q+"},
+		{"1n2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7b8N", "axb8=N"},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", "a1d1", "Rad1"},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", "h1f1", "Rhf1"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a1a3", "R1a3"},
+		{"k7/8/8/8/8/2Q1Q3/8/2Q1K3 w - - 0 1", "c3d2", "Qc3d2"},
+		{"k7/8/1K6/8/8/8/8/7R w - - 0 1", "h1h8", "Rh8#"},
+		{"5k2/8/8/8/8/8/8/4K2R w K - 0 1", "e1g1", "O-O+"},
+		{"r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3", "f3e5", "Nxe5"},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+		m, ok := board.position.parseMove(c.movement)
+		assert.True(t, ok)
+
+		assert.Equal(t, c.san, board.SAN(m), c.fen)
+	}
+}
+
+func Test_SAN_VariantEnded(t *testing.T) {
+	cases := []struct {
+		variant  Variant
+		fen      string
+		movement string
+		san      string
+	}{
+		{ThreeCheck, "4k3/8/8/8/8/8/8/R3K3 w - - 1+3 0 1", "a1a8", "Ra8+"},
+		{ThreeCheck, "k7/8/1K6/8/8/8/8/7R w - - 2+3 0 1", "h1h8", "Rh8#"},
+		{KingOfTheHill, "4k3/8/8/8/8/3K4/8/8 w - - 0 1", "d3d4", "Kd4"},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.SetVariant(c.variant)
+		err := board.TranslateFEN(c.fen)
+		assert.Nil(t, err, c.fen)
+		m, ok := board.position.parseMove(c.movement)
+		assert.True(t, ok, c.fen)
+
+		assert.Equal(t, c.san, board.SAN(m), c.fen)
+	}
+}
+
+func Test_ParseSAN(t *testing.T) {
+	cases := []struct {
+		fen      string
+		san      string
+		movement string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e4", "e2e4"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Nf3!?", "g1f3"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "exd5", "e4d5"},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 2", "exd6", "e5d6"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "O-O", "e1g1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "0-0-0", "e8c8"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b8=Q+", "b7b8Q"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b8R", "b7b8R"},
+		{"1n2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "axb8=N", "a7b8N"},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", "Rad1", "a1d1"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "R1a3", "a1a3"},
+		{"k7/8/8/8/8/2Q1Q3/8/2Q1K3 w - - 0 1", "Qc3d2", "c3d2"},
+		{"k7/8/1K6/8/8/8/8/7R w - - 0 1", "Rh8#", "h1h8"},
+		{"4k3/7p/8/8/8/8/8/4K3 b - - 0 1", "h5", "h7h5"},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+
+		m, err := board.ParseSAN(c.san)
+
+		assert.Nil(t, err, c.san)
+		assert.Equal(t, c.movement, m.String(), c.san)
+	}
+}
+
+func Test_ParseSAN_Errors(t *testing.T) {
+	cases := []struct {
+		fen string
+		san string
+		err string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e5", "e5 is not a legal move"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "O-O", "O-O is not a legal move"},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "Zz9", `invalid SAN "Zz9"`},
+		{"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "", `invalid SAN ""`},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", "Rd1", "Rd1 is ambiguous"},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b8", "b8 is not a legal move"},
+		{"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", "e3=Q", "e3=Q is not a legal move"},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.TranslateFEN(c.fen)
+
+		_, err := board.ParseSAN(c.san)
+
+		assert.EqualError(t, err, c.err, c.san)
+	}
+}
+
+func Test_SAN_ParseSAN_RoundTrip(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+
+	for _, m := range board.LegalMoves() {
+		parsed, err := board.ParseSAN(board.SAN(m))
+
+		assert.Nil(t, err)
+		assert.Equal(t, m, parsed)
+	}
+}
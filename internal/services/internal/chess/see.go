@@ -0,0 +1,77 @@
+package chess
+
+// piece values in centipawns used by SEE, indexed by piece type.
+// king value is high enough to never be traded.
+var seeValues = [6]int{pawn: 100, knight: 300, bishop: 300, rook: 500, queen: 900, king: 20000}
+
+// SEE returns the static exchange evaluation of m in centipawns: material won or lost by
+// the player who makes m after every capture and recapture on its target square,
+// both players capturing with their least valuable piece and stopping when it suits them.
+// sliders behind other attackers (x-rays) take part once the square in front is empty,
+// pins are not taken into account.
+// a positive value is a winning capture, zero an equal trade and a negative value loses material.
+// m must be a move of board position.
+func (board Board) SEE(m Move) int {
+	return board.position.see(m)
+}
+
+func (p *position) see(m Move) int {
+	if m.flags&flagCastle != 0 {
+		return 0
+	}
+
+	gain := make([]int, 1, 32)
+	occupied := p.occupied()
+	onSquare := m.piece.kind()
+
+	if m.captured != noPiece {
+		gain[0] = seeValues[m.captured.kind()]
+	}
+	if m.flags&flagEnPassant != 0 {
+		occupied &^= newSquare(m.to.file(), m.from.rank()).bitboard()
+	}
+	if m.promotion != noPiece {
+		gain[0] += seeValues[m.promotion.kind()] - seeValues[pawn]
+		onSquare = m.promotion.kind()
+	}
+	if m.flags&flagDrop == 0 {
+		occupied &^= m.from.bitboard()
+	}
+
+	side := p.side.other()
+	for {
+		from, t := p.leastValuableAttacker(m.to, side, occupied)
+		if from == noSquare {
+			break
+		}
+
+		// material side wins capturing the piece on square
+		gain = append(gain, seeValues[onSquare]-gain[len(gain)-1])
+
+		occupied &^= from.bitboard()
+		onSquare = t
+		side = side.other()
+	}
+
+	// every player chooses between standing pat and capturing, from the last capture to the first
+	for d := len(gain) - 1; d > 0; d-- {
+		if gain[d] > -gain[d-1] {
+			gain[d-1] = -gain[d]
+		}
+	}
+
+	return gain[0]
+}
+
+// returns the square and type of the least valuable piece of color c attacking s
+// with the given occupancy, noSquare if there is not any
+func (p *position) leastValuableAttacker(s square, c color, occupied bitboard) (square, pieceType) {
+	attackers := p.attackersTo(s, occupied) & p.colors[c] & occupied
+	for t := pawn; t <= king; t++ {
+		if found := attackers & p.bitboardOf(c, t); found != 0 {
+			return found.first(), t
+		}
+	}
+
+	return noSquare, noPieceType
+}
@@ -0,0 +1,38 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SEE(t *testing.T) {
+	cases := []struct {
+		name     string
+		fen      string
+		move     string
+		expected int
+	}{
+		{"undefended pawn", "1k1r4/1pp4p/p7/4p3/8/P5P1/1PP4P/2K1R3 w - - 0 1", "e1e5", 100},
+		{"defended pawn", "1k1r3q/1ppn3p/p4b2/4p3/8/P2N2P1/1PP1R1BP/2K1Q3 w - - 0 1", "d3e5", -200},
+		{"pawn takes defended knight", "4k3/8/3p4/4n3/3P4/8/8/4K3 w - - 0 1", "d4e5", 200},
+		{"equal trade", "4k3/8/3p4/4n3/8/5N2/8/4K3 w - - 0 1", "f3e5", 0},
+		{"x-ray rook behind rook", "3rk3/3r4/8/3p4/8/8/3R4/3RK3 w - - 0 1", "d2d5", -400},
+		{"x-ray queen behind rook wins", "4k3/8/8/3p4/8/3R4/3R4/3QK3 w - - 0 1", "d3d5", 100},
+		{"quiet move to attacked square", "4k3/8/3p4/8/4N3/8/8/4K3 w - - 0 1", "e4c5", -300},
+		{"en passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", 100},
+		{"king recaptures", "4k3/8/4p3/3r4/2K5/8/3R4/8 w - - 0 1", "d2d5", 100},
+		{"king can not recapture defended piece", "4k3/5b2/4p3/3r4/2K5/8/3R4/8 w - - 0 1", "d2d5", 0},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		err := board.TranslateFEN(c.fen)
+		assert.Nil(t, err, c.name)
+
+		m, err := board.ParseMove(c.move)
+		assert.Nil(t, err, c.name)
+
+		assert.Equal(t, c.expected, board.SEE(m), c.name)
+	}
+}
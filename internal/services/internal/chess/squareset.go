@@ -0,0 +1,70 @@
+package chess
+
+type (
+	// SquareSet is a set of squares as a bitboard, bit 0 is a1 and bit 63 is h8.
+	// it lets evaluators read a position without parsing square names.
+	SquareSet uint64
+
+	// Square is a square index from 0 (a1) to 63 (h8), rank by rank
+	Square int
+)
+
+// File returns square file from 0 (a) to 7 (h)
+func (s Square) File() int {
+	return square(s).file()
+}
+
+// Rank returns square rank from 0 (first) to 7 (eighth)
+func (s Square) Rank() int {
+	return square(s).rank()
+}
+
+// String returns square in algebraic notation like "e4"
+func (s Square) String() string {
+	return square(s).String()
+}
+
+// Count returns how many squares the set has
+func (s SquareSet) Count() int {
+	return bitboard(s).count()
+}
+
+// Has returns true if sq is in the set
+func (s SquareSet) Has(sq Square) bool {
+	return bitboard(s).has(square(sq))
+}
+
+// Pop removes and returns the lowest square of the set, it must not be empty
+func (s *SquareSet) Pop() Square {
+	b := bitboard(*s)
+	sq := b.pop()
+	*s = SquareSet(b)
+	return Square(sq)
+}
+
+// Pieces returns squares of pieces p, like chess.WKnight
+func (board *Board) Pieces(p Piece) SquareSet {
+	pc := pieceFromSymbol(p)
+	if pc == noPiece {
+		return 0
+	}
+
+	return SquareSet(board.position.pieces[pc])
+}
+
+// PiecesOf returns squares of the pieces of color turn, "w" or "b" like in FEN
+func (board *Board) PiecesOf(turn string) SquareSet {
+	return SquareSet(board.position.colors[colorOf(turn)])
+}
+
+// AttacksFrom returns squares attacked by the piece on s, with own pieces included
+// since they are defended. it is empty if s is empty.
+func (board *Board) AttacksFrom(s Square) SquareSet {
+	return SquareSet(board.position.pieceAttacks(square(s)))
+}
+
+// AttackersTo returns squares of the pieces of color by attacking s, by must be "w" or "b" like in FEN
+func (board *Board) AttackersTo(s Square, by string) SquareSet {
+	p := &board.position
+	return SquareSet(p.attackersTo(square(s), p.occupied()) & p.colors[colorOf(by)])
+}
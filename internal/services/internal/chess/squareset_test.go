@@ -0,0 +1,48 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// names of the squares of set, popping them in order
+func setNames(set SquareSet) []string {
+	names := []string{}
+	for set != 0 {
+		names = append(names, set.Pop().String())
+	}
+
+	return names
+}
+
+func Test_SquareSet(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/3p4/8/2N2N2/8/3RK3 w - - 0 1")
+	assert.Nil(t, err)
+
+	knights := board.Pieces(WKnight)
+	assert.Equal(t, 2, knights.Count())
+	assert.True(t, knights.Has(Square(18)))
+	assert.Equal(t, []string{"c3", "f3"}, setNames(knights))
+	assert.Equal(t, 2, knights.Count())
+	assert.Equal(t, SquareSet(0), board.Pieces(Piece("x")))
+	assert.Equal(t, []string{"d5", "e8"}, setNames(board.PiecesOf("b")))
+
+	c3 := knights.Pop()
+	assert.Equal(t, 2, c3.File())
+	assert.Equal(t, 2, c3.Rank())
+}
+
+func Test_AttacksFrom_AttackersTo(t *testing.T) {
+	board := Board{}
+	err := board.TranslateFEN("4k3/8/8/3p4/8/2N2N2/8/3RK3 w - - 0 1")
+	assert.Nil(t, err)
+
+	for _, name := range []string{"d1", "c3", "d5", "e8", "e4"} {
+		s, _ := parseSquare(name)
+		assert.ElementsMatch(t, board.Attacks(name), setNames(board.AttacksFrom(Square(s))), name)
+		assert.ElementsMatch(t, board.AttackersOf(name, "w"), setNames(board.AttackersTo(Square(s), "w")), name)
+		assert.ElementsMatch(t, board.AttackersOf(name, "b"), setNames(board.AttackersTo(Square(s), "b")), name)
+	}
+}
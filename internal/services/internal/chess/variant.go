@@ -0,0 +1,428 @@
+package chess
+
+type (
+	// Variant is a set of rules a Board is played with. Standard rules are used
+	// unless a Board is given another variant, which can change when a move is legal
+	// and when the game is over.
+	Variant interface {
+		// Name is the variant name as written in PGN Variant tag
+		Name() string
+
+		// StartingFEN is the FEN of variant initial position
+		StartingFEN() string
+
+		// returns true if m, which took p to after, is legal
+		legal(p *position, m Move, after *position) bool
+
+		// changes legal moves of p generated with standard rules,
+		// adding or removing the ones variant rules differ on
+		adjust(p *position, moves []Move) []Move
+
+		// returns true if side to move of p is in check
+		inCheck(p *position) bool
+
+		// updates variant state of p after m has been made
+		afterMove(p *position, m Move)
+
+		// returns how variant rules end the game in p, NoTermination if they do not
+		outcome(p *position) Outcome
+
+		// returns true if no side has enough material to win
+		insufficientMaterial(p *position) bool
+
+		// returns a FENError if p is not a legal position of the variant
+		validate(p *position) error
+	}
+
+	standard      struct{}
+	threeCheck    struct{ standard }
+	kingOfTheHill struct{ standard }
+	racingKings   struct{ standard }
+	atomic        struct{ standard }
+	antichess     struct{ standard }
+)
+
+const (
+	ThirdCheck      Termination = "third_check"
+	KingInTheCenter Termination = "king_in_the_center"
+	RaceFinished    Termination = "race_finished"
+	KingExploded    Termination = "king_exploded"
+	NoMovesLeft     Termination = "no_moves_left"
+)
+
+const (
+	RuleCheckNotAllowed FENRule = "check_not_allowed"
+
+	standardFEN    = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	racingKingsFEN = "8/8/8/8/8/8/krbnNBRK/qrbnNBRQ w - - 0 1"
+	antichessFEN   = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1"
+
+	// checks a player has to give to win a Three-check game
+	checksToWin = 3
+
+	center       bitboard = 0x0000001818000000
+	lightSquares bitboard = 0x55aa55aa55aa55aa
+)
+
+var (
+	Standard      Variant = standard{}
+	ThreeCheck    Variant = threeCheck{}
+	KingOfTheHill Variant = kingOfTheHill{}
+	RacingKings   Variant = racingKings{}
+	Atomic        Variant = atomic{}
+	Antichess     Variant = antichess{}
+	Crazyhouse    Variant = crazyhouse{}
+
+	variants = []Variant{Standard, ThreeCheck, KingOfTheHill, RacingKings, Atomic, Antichess, Crazyhouse}
+
+	notFinished = Outcome{Result: resultNotFinished, Termination: NoTermination}
+)
+
+// VariantByName returns the variant with the given PGN Variant tag name, like "Three-check".
+// returns false if there is no variant with that name.
+func VariantByName(name string) (Variant, bool) {
+	for _, v := range variants {
+		if v.Name() == name {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// NewVariantBoard returns a board in the initial position of variant v
+func NewVariantBoard(v Variant) Board {
+	b := Board{}
+	b.SetVariant(v)
+	b.TranslateFEN(v.StartingFEN())
+	return b
+}
+
+// SetVariant changes the rules board is played with, pieces are not moved.
+func (board *Board) SetVariant(v Variant) {
+	board.position.variant = v
+}
+
+// Variant returns the rules board is played with
+func (board Board) Variant() Variant {
+	return board.position.rules()
+}
+
+// Checks returns how many checks each player has given, it is used by Three-check.
+func (board Board) Checks() (byWhite, byBlack int) {
+	return board.position.checks[white], board.position.checks[black]
+}
+
+// returns position variant, Standard if it has not any
+func (p *position) rules() Variant {
+	if p.variant == nil {
+		return Standard
+	}
+
+	return p.variant
+}
+
+// outcome of a game won by color c
+func winOf(c color, termination Termination) Outcome {
+	if c == white {
+		return Outcome{Result: resultWhiteWins, Termination: termination}
+	}
+
+	return Outcome{Result: resultBlackWins, Termination: termination}
+}
+
+func (standard) Name() string {
+	return "Standard"
+}
+
+func (standard) StartingFEN() string {
+	return standardFEN
+}
+
+// own king can not be left under attack
+func (standard) legal(p *position, m Move, after *position) bool {
+	return !after.kingAttacked(p.side)
+}
+
+func (standard) adjust(p *position, moves []Move) []Move {
+	return moves
+}
+
+func (standard) inCheck(p *position) bool {
+	return p.kingAttacked(p.side)
+}
+
+func (standard) afterMove(p *position, m Move) {}
+
+func (standard) outcome(p *position) Outcome {
+	return notFinished
+}
+
+// lone kings, a single minor piece, or only bishops standing on squares of the same color
+func (standard) insufficientMaterial(p *position) bool {
+	for _, c := range []color{white, black} {
+		if p.bitboardOf(c, pawn)|p.bitboardOf(c, rook)|p.bitboardOf(c, queen) != 0 {
+			return false
+		}
+	}
+
+	knights := p.bitboardOf(white, knight) | p.bitboardOf(black, knight)
+	bishops := p.bitboardOf(white, bishop) | p.bitboardOf(black, bishop)
+	if (knights | bishops).count() <= 1 {
+		return true
+	}
+
+	return knights == 0 && (bishops&lightSquares == 0 || bishops&^lightSquares == 0)
+}
+
+func (standard) validate(p *position) error {
+	return firstError(p.validateKings, p.validateMaterial, p.validatePawns, p.validateTurn,
+		p.validateCastling, p.validateEnPassant)
+}
+
+func (threeCheck) Name() string {
+	return "Three-check"
+}
+
+// counts the check given by the player who has just moved
+func (threeCheck) afterMove(p *position, m Move) {
+	if p.inCheck() {
+		c := p.side.other()
+		p.hash ^= checkKey(c, p.checks[c]) ^ checkKey(c, p.checks[c]+1)
+		p.checks[c]++
+	}
+}
+
+func (threeCheck) outcome(p *position) Outcome {
+	for _, c := range []color{white, black} {
+		if p.checks[c] >= checksToWin {
+			return winOf(c, ThirdCheck)
+		}
+	}
+
+	return notFinished
+}
+
+// any piece can give check, so only two lone kings are a draw
+func (threeCheck) insufficientMaterial(p *position) bool {
+	return p.occupied() == p.bitboardOf(white, king)|p.bitboardOf(black, king)
+}
+
+func (kingOfTheHill) Name() string {
+	return "King of the Hill"
+}
+
+func (kingOfTheHill) outcome(p *position) Outcome {
+	for _, c := range []color{white, black} {
+		if p.bitboardOf(c, king)&center != 0 {
+			return winOf(c, KingInTheCenter)
+		}
+	}
+
+	return notFinished
+}
+
+// a lone king can still win reaching the center
+func (kingOfTheHill) insufficientMaterial(p *position) bool {
+	return false
+}
+
+func (racingKings) Name() string {
+	return "Racing Kings"
+}
+
+func (racingKings) StartingFEN() string {
+	return racingKingsFEN
+}
+
+// giving check is not allowed
+func (r racingKings) legal(p *position, m Move, after *position) bool {
+	return r.standard.legal(p, m, after) && !after.inCheck()
+}
+
+// first king reaching the last rank wins. black still has a move to draw
+// reaching it too when white king gets there first.
+func (racingKings) outcome(p *position) Outcome {
+	whiteArrived := p.bitboardOf(white, king)&rank8 != 0
+	blackArrived := p.bitboardOf(black, king)&rank8 != 0
+
+	switch {
+	case whiteArrived && blackArrived:
+		return Outcome{Result: resultDraw, Termination: RaceFinished}
+	case blackArrived:
+		return winOf(black, RaceFinished)
+	case whiteArrived && p.side == black:
+		for _, m := range p.generateLegalMoves() {
+			if m.from == p.kingSquare(black) && rank8.has(m.to) {
+				return notFinished
+			}
+		}
+
+		return winOf(white, RaceFinished)
+	case whiteArrived:
+		return winOf(white, RaceFinished)
+	}
+
+	return notFinished
+}
+
+// kings always can race to the last rank
+func (racingKings) insufficientMaterial(p *position) bool {
+	return false
+}
+
+func (r racingKings) validate(p *position) error {
+	if err := r.standard.validate(p); err != nil {
+		return err
+	}
+
+	if p.inCheck() {
+		return newFENError(FENPiecePlacement, RuleCheckNotAllowed, "kings can not be in check in Racing Kings")
+	}
+
+	return nil
+}
+
+func (atomic) Name() string {
+	return "Atomic"
+}
+
+// kings can not capture and own king can not explode. exploding the opponent
+// king wins even if own king is attacked, and connected kings can not give check.
+func (a atomic) legal(p *position, m Move, after *position) bool {
+	us := p.side
+	if m.flags&flagCapture != 0 && m.piece.kind() == king {
+		return false
+	}
+
+	if after.kingSquare(us) == noSquare {
+		return false
+	}
+
+	if after.kingSquare(us.other()) == noSquare || kingsConnected(after) {
+		return true
+	}
+
+	return a.standard.legal(p, m, after)
+}
+
+func (a atomic) inCheck(p *position) bool {
+	return !kingsConnected(p) && a.standard.inCheck(p)
+}
+
+// captures explode, removing the capturing piece and every piece
+// but pawns next to the target square
+func (atomic) afterMove(p *position, m Move) {
+	if m.flags&flagCapture == 0 {
+		return
+	}
+
+	exploded := (kingAttacks[m.to] &^ (p.bitboardOf(white, pawn) | p.bitboardOf(black, pawn))) & p.occupied()
+	exploded |= m.to.bitboard()
+
+	p.hash ^= castlingKey(p.castling)
+	for exploded != 0 {
+		s := exploded.pop()
+		if p.squares[s].kind() == king {
+			p.castling &^= castlingOf(p.squares[s].color())
+		}
+		for i, rook := range p.castleRooks {
+			if rook == s {
+				p.castling &^= 1 << i
+			}
+		}
+
+		p.remove(s)
+	}
+	p.hash ^= castlingKey(p.castling)
+}
+
+func (atomic) outcome(p *position) Outcome {
+	for _, c := range []color{white, black} {
+		if p.bitboardOf(c, king) == 0 {
+			return winOf(c.other(), KingExploded)
+		}
+	}
+
+	return notFinished
+}
+
+// a king and at most one minor piece can not explode the opponent king
+func (atomic) insufficientMaterial(p *position) bool {
+	kings := p.bitboardOf(white, king) | p.bitboardOf(black, king)
+	minors := p.bitboardOf(white, knight) | p.bitboardOf(black, knight) |
+		p.bitboardOf(white, bishop) | p.bitboardOf(black, bishop)
+
+	return p.occupied()&^(kings|minors) == 0 && minors.count() <= 1
+}
+
+// returns true if both kings are in adjacent squares
+func kingsConnected(p *position) bool {
+	k := p.kingSquare(white)
+	return k != noSquare && kingAttacks[k]&p.bitboardOf(black, king) != 0
+}
+
+func (antichess) Name() string {
+	return "Antichess"
+}
+
+func (antichess) StartingFEN() string {
+	return antichessFEN
+}
+
+// king is a common piece that can be left attacked
+func (antichess) legal(p *position, m Move, after *position) bool {
+	return true
+}
+
+// pawns can be promoted to king too, and capturing is mandatory
+func (antichess) adjust(p *position, moves []Move) []Move {
+	for _, m := range moves {
+		if m.promotion.kind() == queen {
+			m.promotion = makePiece(p.side, king)
+			moves = append(moves, m)
+		}
+	}
+
+	captures := make([]Move, 0, len(moves))
+	for _, m := range moves {
+		if m.flags&flagCapture != 0 {
+			captures = append(captures, m)
+		}
+	}
+
+	if len(captures) == 0 {
+		return moves
+	}
+
+	return captures
+}
+
+func (antichess) inCheck(p *position) bool {
+	return false
+}
+
+// player who loses all its pieces, or can not move, wins
+func (antichess) outcome(p *position) Outcome {
+	if len(p.generateLegalMoves()) == 0 {
+		return winOf(p.side, NoMovesLeft)
+	}
+
+	return notFinished
+}
+
+// only a bishop for each player on squares of different color is a draw
+func (antichess) insufficientMaterial(p *position) bool {
+	whiteBishop, blackBishop := p.bitboardOf(white, bishop), p.bitboardOf(black, bishop)
+	if p.colors[white] != whiteBishop || p.colors[black] != blackBishop ||
+		whiteBishop.count() != 1 || blackBishop.count() != 1 {
+		return false
+	}
+
+	return (whiteBishop&lightSquares == 0) != (blackBishop&lightSquares == 0)
+}
+
+// there can be any number of kings, even none
+func (antichess) validate(p *position) error {
+	return firstError(p.validateMaterial, p.validatePawns, p.validateCastling, p.validateEnPassant)
+}
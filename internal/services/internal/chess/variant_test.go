@@ -0,0 +1,301 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VariantByName(t *testing.T) {
+	for _, v := range []Variant{Standard, ThreeCheck, KingOfTheHill, RacingKings, Atomic, Antichess, Crazyhouse} {
+		found, ok := VariantByName(v.Name())
+
+		assert.True(t, ok)
+		assert.Equal(t, v, found)
+	}
+
+	_, ok := VariantByName("Horde")
+	assert.False(t, ok)
+}
+
+func Test_NewVariantBoard(t *testing.T) {
+	board := NewVariantBoard(RacingKings)
+
+	assert.Equal(t, RacingKings, board.Variant())
+	assert.Equal(t, "8/8/8/8/8/8/krbnNBRK/qrbnNBRQ w - - 0 1", board.FEN())
+	assert.Equal(t, Standard, NewBoard().Variant())
+}
+
+func Test_ThreeCheck_CountsChecks(t *testing.T) {
+	board := NewVariantBoard(ThreeCheck)
+
+	for _, m := range []string{"e2e4", "f7f6", "d1h5"} {
+		board.MakeMove(m)
+	}
+
+	byWhite, byBlack := board.Checks()
+	assert.Equal(t, 1, byWhite)
+	assert.Equal(t, 0, byBlack)
+	assert.Equal(t, "rnbqkbnr/ppppp1pp/5p2/7Q/4P3/8/PPPP1PPP/RNB1KBNR b KQkq - 2+3 1 2", board.FEN())
+
+	board.UnmakeMove()
+	byWhite, _ = board.Checks()
+	assert.Equal(t, 0, byWhite)
+}
+
+func Test_ThreeCheck_ThirdCheckWins(t *testing.T) {
+	board := Board{}
+	board.SetVariant(ThreeCheck)
+	err := board.TranslateFEN("4k3/8/8/8/8/8/8/R3K3 w - - 1+3 0 1")
+	assert.Nil(t, err)
+
+	board.MakeMove("a1a8")
+
+	assert.Equal(t, Outcome{Result: "1-0", Termination: ThirdCheck}, board.Outcome())
+	assert.Empty(t, board.LegalMoves())
+	assert.Equal(t, "R3k3/8/8/8/8/8/8/4K3 b - - 0+3 1 1", board.FEN())
+}
+
+func Test_ThreeCheck_InsufficientMaterial(t *testing.T) {
+	board := Board{}
+	board.SetVariant(ThreeCheck)
+	board.TranslateFEN("4k3/8/8/8/8/8/8/N3K3 w - - 0 1")
+
+	assert.False(t, board.IsInsufficientMaterial())
+
+	board.TranslateFEN("4k3/8/8/8/8/8/8/4K3 w - - 0 1")
+
+	assert.True(t, board.IsInsufficientMaterial())
+}
+
+func Test_KingOfTheHill(t *testing.T) {
+	board := Board{}
+	board.SetVariant(KingOfTheHill)
+	board.TranslateFEN("4k3/8/8/8/8/3K4/8/8 w - - 0 1")
+
+	assert.False(t, board.IsInsufficientMaterial())
+	assert.Equal(t, NoTermination, board.Outcome().Termination)
+
+	board.MakeMove("d3d4")
+
+	assert.Equal(t, Outcome{Result: "1-0", Termination: KingInTheCenter}, board.Outcome())
+	assert.Empty(t, board.LegalMoves())
+}
+
+func Test_RacingKings_CheckIsNotAllowed(t *testing.T) {
+	board := Board{}
+	board.TranslateFEN("8/8/8/8/8/8/k7/6RK w - - 0 1")
+	assert.Contains(t, board.AvailableLegalMoves(), "g1g2")
+
+	board.SetVariant(RacingKings)
+	assert.NotContains(t, board.AvailableLegalMoves(), "g1g2")
+	assert.Contains(t, board.AvailableLegalMoves(), "g1g3")
+}
+
+func Test_RacingKings_Outcome(t *testing.T) {
+	cases := []struct {
+		fen      string
+		moves    []string
+		expected Outcome
+	}{
+		{"8/K7/7k/8/8/8/8/8 w - - 0 1", []string{"a7a8"}, Outcome{Result: "1-0", Termination: RaceFinished}},
+		{"8/K6k/8/8/8/8/8/8 w - - 0 1", []string{"a7a8"}, Outcome{Result: "*", Termination: NoTermination}},
+		{"8/K6k/8/8/8/8/8/8 w - - 0 1", []string{"a7a8", "h7h8"}, Outcome{Result: "1/2-1/2", Termination: RaceFinished}},
+		{"8/K6k/8/8/8/8/8/8 w - - 0 1", []string{"a7a8", "h7g6"}, Outcome{Result: "1-0", Termination: RaceFinished}},
+		{"8/K6k/8/8/8/8/8/8 b - - 0 1", []string{"h7h8"}, Outcome{Result: "0-1", Termination: RaceFinished}},
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.SetVariant(RacingKings)
+		err := board.TranslateFEN(c.fen)
+		assert.Nil(t, err)
+
+		for _, m := range c.moves {
+			board.MakeMove(m)
+		}
+
+		assert.Equal(t, c.expected, board.Outcome(), c.moves)
+	}
+}
+
+func Test_RacingKings_InvalidFEN(t *testing.T) {
+	board := Board{}
+	board.SetVariant(RacingKings)
+
+	err := board.TranslateFEN("8/8/8/8/8/8/k6R/7K b - - 0 1")
+
+	assert.EqualError(t, err, "invalid FEN piece_placement: kings can not be in check in Racing Kings")
+	assert.Nil(t, board.TranslateFEN(racingKingsFEN))
+}
+
+func Test_Atomic_CaptureExplodes(t *testing.T) {
+	board := NewVariantBoard(Atomic)
+
+	for _, m := range []string{"e2e4", "d7d5", "e4d5"} {
+		board.MakeMove(m)
+	}
+
+	assert.Equal(t, "rnbqkbnr/ppp1pppp/8/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2", board.FEN())
+
+	board.MakeMove("d8d2")
+
+	assert.Equal(t, "rnb1kbnr/ppp1pppp/8/8/8/8/PPP2PPP/RN3BNR w kq - 0 3", board.FEN())
+	assert.Equal(t, Outcome{Result: "0-1", Termination: KingExploded}, board.Outcome())
+	assert.Empty(t, board.LegalMoves())
+}
+
+func Test_Atomic_Legality(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Atomic)
+	err := board.TranslateFEN("8/8/8/8/3k4/3K4/3p4/8 w - - 0 1")
+	assert.Nil(t, err)
+
+	moves := board.AvailableLegalMoves()
+
+	// connected kings do not give check, and kings can not capture
+	assert.False(t, board.IsCheck)
+	assert.Contains(t, moves, "d3e3")
+	assert.Contains(t, moves, "d3c4")
+	assert.NotContains(t, moves, "d3d2")
+}
+
+func Test_Atomic_ExplodingKingWinsOutOfCheck(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Atomic)
+	err := board.TranslateFEN("3nk3/4p3/8/8/8/8/4r3/3RK3 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.True(t, board.IsCheck)
+	assert.Contains(t, board.AvailableLegalMoves(), "d1d8")
+
+	m, err := board.ParseSAN("Rxd8")
+	assert.Nil(t, err)
+	board.Play(m)
+
+	assert.Equal(t, Outcome{Result: "1-0", Termination: KingExploded}, board.Outcome())
+}
+
+func Test_Antichess_CapturesAreMandatory(t *testing.T) {
+	board := NewVariantBoard(Antichess)
+
+	board.MakeMove("e2e4")
+	board.MakeMove("d7d5")
+
+	assert.Equal(t, []string{"e4d5"}, board.AvailableLegalMoves())
+	assert.False(t, board.IsCheck)
+}
+
+func Test_Antichess_PromotionToKing(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Antichess)
+	err := board.TranslateFEN("8/4P3/8/8/8/8/8/k7 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.ElementsMatch(t, []string{"e7e8Q", "e7e8R", "e7e8N", "e7e8B", "e7e8K"}, board.AvailableLegalMoves())
+
+	m, err := board.ParseSAN("e8=K")
+	assert.Nil(t, err)
+	assert.Equal(t, WKing, m.Promotion())
+}
+
+func Test_Antichess_LosingAllPiecesWins(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Antichess)
+	err := board.TranslateFEN("8/8/8/8/8/8/1p6/R7 b - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, NoTermination, board.Outcome().Termination)
+
+	board.MakeMove("b2a1Q")
+
+	assert.Equal(t, Outcome{Result: "1-0", Termination: NoMovesLeft}, board.Outcome())
+
+	// a blocked player wins too
+	err = board.TranslateFEN("8/8/8/8/8/p7/P7/8 w - - 0 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, Outcome{Result: "1-0", Termination: NoMovesLeft}, board.Outcome())
+}
+
+func Test_Crazyhouse_CapturedPiecesAreDropped(t *testing.T) {
+	board := NewVariantBoard(Crazyhouse)
+
+	for _, m := range []string{"e2e4", "d7d5", "e4d5", "d8d5", "b1c3", "d5a5"} {
+		board.MakeMove(m)
+	}
+
+	assert.Equal(t, map[Piece]int{WPawn: 1}, board.Pocket("w"))
+	assert.Equal(t, map[Piece]int{BPawn: 1}, board.Pocket("b"))
+	assert.Equal(t, "rnb1kbnr/ppp1pppp/8/q7/8/2N5/PPPP1PPP/R1BQKBNR[Pp] w KQkq - 2 4", board.FEN())
+
+	m, err := board.ParseMove("P@d5")
+	assert.Nil(t, err)
+	assert.True(t, m.IsDrop())
+	assert.Equal(t, "", m.From())
+	assert.Equal(t, "d5", m.To())
+	assert.Equal(t, "P@d5", board.SAN(m))
+
+	board.Play(m)
+
+	assert.Equal(t, "rnb1kbnr/ppp1pppp/8/q2P4/8/2N5/PPPP1PPP/R1BQKBNR[p] b KQkq - 0 4", board.FEN())
+	assert.Equal(t, []string{"e2e4", "d7d5", "e4d5", "d8d5", "b1c3", "d5a5", "P@d5"}, board.MovesHistory)
+
+	m, err = board.ParseSAN("@e3")
+	assert.Nil(t, err)
+	assert.Equal(t, "P@e3", m.String())
+
+	board.UnmakeMove()
+	assert.Equal(t, "rnb1kbnr/ppp1pppp/8/q7/8/2N5/PPPP1PPP/R1BQKBNR[Pp] w KQkq - 2 4", board.FEN())
+}
+
+func Test_Crazyhouse_DropLegality(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Crazyhouse)
+	err := board.TranslateFEN("4k3/8/8/8/8/8/8/r3K3[Np] w - - 0 1")
+	assert.Nil(t, err)
+
+	moves := board.AvailableLegalMoves()
+
+	// drops can block checks, and only the side to move drops
+	assert.Contains(t, moves, "N@b1")
+	assert.NotContains(t, moves, "N@e4")
+	assert.NotContains(t, moves, "P@d1")
+
+	board.MakeMove("e1e2")
+	moves = board.AvailableLegalMoves()
+	assert.Contains(t, moves, "P@e4")
+	assert.NotContains(t, moves, "P@e1")
+}
+
+func Test_Crazyhouse_PromotedPiecesGoBackAsPawns(t *testing.T) {
+	board := Board{}
+	board.SetVariant(Crazyhouse)
+	err := board.TranslateFEN("3rk3/4P3/8/8/8/8/8/4K3[] w - - 0 1")
+	assert.Nil(t, err)
+
+	board.MakeMove("e7d8Q")
+	assert.Equal(t, "3Q~k3/8/8/8/8/8/8/4K3[R] b - - 0 1", board.FEN())
+
+	board.MakeMove("e8d8")
+	assert.Equal(t, "3k4/8/8/8/8/8/8/4K3[Rp] w - - 0 2", board.FEN())
+}
+
+func Test_Crazyhouse_FEN(t *testing.T) {
+	fen := "r1bqkb1r/pppp1ppp/2n5/4p3/4P3/5N2/PPP2PPP/RNBQ~KB1R[Np] b KQkq - 3 3"
+	board := Board{}
+	board.SetVariant(Crazyhouse)
+
+	assert.Nil(t, board.TranslateFEN(fen))
+	assert.Equal(t, fen, board.FEN())
+	assert.Equal(t, map[Piece]int{WKnight: 1}, board.Pocket("w"))
+
+	// pockets take part in hash
+	other := Board{}
+	other.SetVariant(Crazyhouse)
+	other.TranslateFEN("r1bqkb1r/pppp1ppp/2n5/4p3/4P3/5N2/PPP2PPP/RNBQ~KB1R[N] b KQkq - 3 3")
+	assert.NotEqual(t, board.Hash(), other.Hash())
+
+	err := board.TranslateFEN("4k3/8/8/8/8/8/8/4K3[Kq] w - - 0 1")
+	assert.EqualError(t, err, "invalid FEN pocket: unknown piece 'K' in pocket")
+}
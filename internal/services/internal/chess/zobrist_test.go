@@ -0,0 +1,174 @@
+package chess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hash_IncrementalEqualsFromScratch(t *testing.T) {
+	fens := []string{
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+	}
+
+	var walk func(p position, depth int)
+	walk = func(p position, depth int) {
+		assert.Equal(t, p.computeHash(), p.hash)
+		if depth == 0 {
+			return
+		}
+
+		for _, m := range p.legalMoves() {
+			after := p
+			after.makeMove(m)
+			walk(after, depth-1)
+		}
+	}
+
+	for _, fen := range fens {
+		p, err := parsePosition(fen, false)
+		assert.Nil(t, err)
+
+		walk(p, 3)
+	}
+}
+
+func Test_Hash_Transposition(t *testing.T) {
+	b1 := NewBoard()
+	b2 := NewBoard()
+
+	for _, m := range []string{"g1f3", "b8c6", "b1c3"} {
+		b1.MakeMove(m)
+	}
+	for _, m := range []string{"b1c3", "b8c6", "g1f3"} {
+		b2.MakeMove(m)
+	}
+
+	assert.Equal(t, b1.Hash(), b2.Hash())
+	assert.NotEqual(t, NewBoard().Hash(), b1.Hash())
+}
+
+func Test_Hash_UnmakeMove(t *testing.T) {
+	board := NewBoard()
+	initial := board.Hash()
+
+	board.MakeMove("e2e4")
+	board.MakeMove("e7e5")
+	board.UnmakeMove()
+	board.UnmakeMove()
+
+	assert.Equal(t, initial, board.Hash())
+}
+
+func Test_Hash_InPassantOnlyIfCapturable(t *testing.T) {
+	assert := assert.New(t)
+	withSquare, withoutSquare := Board{}, Board{}
+
+	withSquare.TranslateFEN("4k3/8/8/8/4P3/8/8/4K3 b - e3 0 1")
+	withoutSquare.TranslateFEN("4k3/8/8/8/4P3/8/8/4K3 b - - 0 1")
+	assert.Equal(withoutSquare.Hash(), withSquare.Hash())
+
+	withSquare.TranslateFEN("4k3/8/8/8/3pP3/8/8/4K3 b - e3 0 1")
+	withoutSquare.TranslateFEN("4k3/8/8/8/3pP3/8/8/4K3 b - - 0 1")
+	assert.NotEqual(withoutSquare.Hash(), withSquare.Hash())
+}
+
+func Test_Hash_CastlingAndTurn(t *testing.T) {
+	hashes := map[uint64]string{}
+	fens := []string{
+		"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w Kkq - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w KQq - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w - - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1",
+	}
+
+	for _, fen := range fens {
+		board := Board{}
+		board.TranslateFEN(fen)
+		hashes[board.Hash()] = fen
+	}
+
+	assert.Len(t, hashes, len(fens))
+}
+
+func Test_Hash_PolyglotKeys(t *testing.T) {
+	// keys from Polyglot book format specification
+	cases := []struct {
+		moves []string
+		key   uint64
+	}{
+		{nil, 0x463b96181691fc9c},
+		{[]string{"e2e4"}, 0x823c9b50fd114196},
+		{[]string{"e2e4", "d7d5"}, 0x0756b94461c50fb0},
+		{[]string{"e2e4", "d7d5", "e4e5"}, 0x662fafb965db29d4},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5"}, 0x22a48b5a8e47ff78},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2"}, 0x652a607ca3f242c1},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2", "e8f7"}, 0x00fdd303c946bdd9},
+		{[]string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4"}, 0x3c8123ea7b067637},
+		{[]string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4", "b4c3", "a1a3"}, 0x5c3f9b829b279560},
+	}
+
+	for _, c := range cases {
+		board := NewBoard()
+		for _, m := range c.moves {
+			board.MakeMove(m)
+		}
+
+		assert.Equal(t, c.key, board.Hash(), c.moves)
+	}
+}
+
+func Test_Hash_VariantsIncrementalEqualsFromScratch(t *testing.T) {
+	cases := []struct {
+		variant Variant
+		fen     string
+	}{
+		{ThreeCheck, "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 2+3 0 1"},
+		{Crazyhouse, "r1b1k2r/pPpp1ppp/2n2n2/4p3/1b2P3/2N2N2/PPPP1PPP/R1B1KB1R[Qq] w KQkq - 0 1"},
+	}
+
+	var walk func(p position, depth int)
+	walk = func(p position, depth int) {
+		assert.Equal(t, p.computeHash(), p.hash)
+		if depth == 0 {
+			return
+		}
+
+		for _, m := range p.legalMoves() {
+			after := p
+			after.makeMove(m)
+			walk(after, depth-1)
+		}
+	}
+
+	for _, c := range cases {
+		board := Board{}
+		board.SetVariant(c.variant)
+		err := board.TranslateFEN(c.fen)
+		assert.Nil(t, err, c.fen)
+
+		walk(board.position, 2)
+	}
+}
+
+func Test_Repetitions_ThreeCheckCountsChecks(t *testing.T) {
+	board := Board{}
+	board.SetVariant(ThreeCheck)
+	err := board.TranslateFEN("4k3/8/8/8/8/8/8/R3K3 w - - 3+3 0 1")
+	assert.Nil(t, err)
+
+	for _, m := range []string{"a1a8", "e8d7", "a8a1", "d7e8"} {
+		board.MakeMove(m)
+	}
+
+	assert.Equal(t, 1, board.Repetitions())
+
+	for _, m := range []string{"a1a2", "e8d8", "a2a1", "d8e8"} {
+		board.MakeMove(m)
+	}
+
+	assert.Equal(t, 2, board.Repetitions())
+}
@@ -0,0 +1,359 @@
+package enginepool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/services/internal/uci"
+)
+
+type (
+	// Engine is an engine process the pool can analyze positions with, like *uci.Client
+	Engine interface {
+		SetOption(ctx context.Context, name, value string) error
+		Analyze(ctx context.Context, fen string, moves []string, limits search.Limits, onInfo func(uci.Info)) (uci.Analysis, error)
+		Close() error
+	}
+
+	// StartFunc starts a new engine process
+	StartFunc func(ctx context.Context) (Engine, error)
+
+	Config struct {
+		// engine processes kept running
+		Size int
+
+		// threads and hash memory in megabytes of all engines together, split between them.
+		// zero keeps engines default values
+		Threads       int
+		HashMegabytes int
+
+		// limits of the search of every position
+		Limits search.Limits
+
+		// times an engine can crash before pool stops restarting it, 3 if it is zero
+		MaxRestarts int
+	}
+
+	// Game to analyze, FEN is empty if it starts from the standard position
+	Game struct {
+		ID    string
+		FEN   string
+		Moves []string
+	}
+
+	GameResult struct {
+		ID string
+
+		// analysis of every position of game, Positions[i] is the one after i moves
+		Positions []uci.Analysis
+
+		// first error found analyzing game positions, if any
+		Err error
+	}
+
+	// Progress of the analysis of a game
+	Progress struct {
+		GameID   string
+		Analyzed int
+		Total    int
+	}
+
+	// Pool keeps engine processes running and shares them between games, so positions
+	// of many games are analyzed at the same time. it can be used from several goroutines.
+	Pool struct {
+		start  StartFunc
+		config Config
+
+		// engines not analyzing any position
+		idle chan *slot
+
+		// closed by Close to cancel analyses in progress
+		done chan struct{}
+
+		mu     sync.Mutex
+		slots  []*slot
+		closed bool
+	}
+
+	// an engine of the pool, engine is nil if it crashed and could not be restarted yet
+	slot struct {
+		engine   Engine
+		restarts int
+	}
+
+	// a position of a game to analyze
+	task struct {
+		game  int
+		ply   int
+		moves []string
+	}
+)
+
+const defaultMaxRestarts = 3
+
+// ErrClosed is returned analyzing positions with a closed pool
+var ErrClosed = errors.New("engine pool is closed")
+
+// New starts config.Size engines with start and sets their Threads and Hash options
+func New(ctx context.Context, start StartFunc, config Config) (*Pool, error) {
+	if config.Size <= 0 {
+		config.Size = 1
+	}
+
+	// every engine needs a thread at least
+	if config.Threads > 0 && config.Threads < config.Size {
+		config.Size = config.Threads
+	}
+
+	if config.MaxRestarts == 0 {
+		config.MaxRestarts = defaultMaxRestarts
+	}
+
+	p := &Pool{start: start, config: config, idle: make(chan *slot, config.Size), done: make(chan struct{})}
+	for i := 0; i < config.Size; i++ {
+		engine, err := p.startEngine(ctx)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+
+		s := &slot{engine: engine}
+		p.slots = append(p.slots, s)
+		p.idle <- s
+	}
+
+	return p, nil
+}
+
+// UCIStarter returns a StartFunc that runs the UCI engine at path with args
+func UCIStarter(path string, args ...string) StartFunc {
+	return func(ctx context.Context) (Engine, error) {
+		return uci.Start(ctx, path, args...)
+	}
+}
+
+// Size returns how many engines the pool has
+func (p *Pool) Size() int {
+	return p.config.Size
+}
+
+// Analyze analyzes every position of games with the pool engines and returns
+// their results in the same order. onProgress is called every time a position
+// is analyzed, it can be nil. if ctx is done positions not analyzed yet fail with ctx error,
+// and with ErrClosed if pool is closed.
+func (p *Pool) Analyze(ctx context.Context, games []Game, onProgress func(Progress)) []GameResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	results := make([]GameResult, len(games))
+	tasks := make(chan task)
+	for i, g := range games {
+		results[i] = GameResult{ID: g.ID, Positions: make([]uci.Analysis, len(g.Moves)+1)}
+	}
+
+	go func() {
+		defer close(tasks)
+		for i, g := range games {
+			for ply := 0; ply <= len(g.Moves); ply++ {
+				select {
+				case tasks <- task{game: i, ply: ply, moves: g.Moves[:ply]}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	analyzed := make([]int, len(games))
+	wg := sync.WaitGroup{}
+	for i := 0; i < p.config.Size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tasks {
+				g := games[t.game]
+				analysis, err := p.analyze(ctx, g.FEN, t.moves)
+
+				mu.Lock()
+				results[t.game].Positions[t.ply] = analysis
+				if err != nil && results[t.game].Err == nil {
+					results[t.game].Err = fmt.Errorf("error analyzing position after %d moves: %w", t.ply, err)
+				}
+				analyzed[t.game]++
+				if onProgress != nil {
+					onProgress(Progress{GameID: g.ID, Analyzed: analyzed[t.game], Total: len(g.Moves) + 1})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// positions never sent to engines
+	for i := range results {
+		if analyzed[i] < len(results[i].Positions) && results[i].Err == nil {
+			results[i].Err = p.err(ctx)
+		}
+	}
+
+	return results
+}
+
+// analyzes a position with the first idle engine, restarting it if it crashes
+func (p *Pool) analyze(ctx context.Context, fen string, moves []string) (uci.Analysis, error) {
+	var s *slot
+	select {
+	case s = <-p.idle:
+	case <-ctx.Done():
+		return uci.Analysis{}, p.err(ctx)
+	}
+	defer func() { p.idle <- s }()
+
+	// a position that crashed an engine is tried again with a new one
+	for attempt := 0; ; attempt++ {
+		if s.engine == nil {
+			if err := p.restart(ctx, s); err != nil {
+				return uci.Analysis{}, err
+			}
+		}
+
+		analysis, err := s.engine.Analyze(ctx, fen, moves, p.config.Limits, nil)
+		if err != nil && p.isClosed() {
+			return analysis, ErrClosed
+		}
+
+		if !errors.Is(err, uci.ErrEngineExited) || attempt > 0 {
+			return analysis, err
+		}
+
+		s.engine.Close()
+		p.setEngine(s, nil)
+	}
+}
+
+func (p *Pool) restart(ctx context.Context, s *slot) error {
+	if s.restarts >= p.config.MaxRestarts {
+		return fmt.Errorf("engine crashed %d times: %w", s.restarts, uci.ErrEngineExited)
+	}
+
+	s.restarts++
+	engine, err := p.startEngine(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.setEngine(s, engine)
+	if s.engine == nil {
+		return ErrClosed
+	}
+
+	return nil
+}
+
+// engines are changed while pool is locked, so Close does not miss them
+func (p *Pool) setEngine(s *slot, engine Engine) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed && engine != nil {
+		engine.Close()
+		engine = nil
+	}
+
+	s.engine = engine
+}
+
+// error of an analysis whose ctx is done, ErrClosed if it was done by Close
+func (p *Pool) err(ctx context.Context) error {
+	if p.isClosed() {
+		return ErrClosed
+	}
+
+	return ctx.Err()
+}
+
+func (p *Pool) isClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Pool) startEngine(ctx context.Context) (Engine, error) {
+	engine, err := p.start(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting engine: %w", err)
+	}
+
+	if p.config.Threads > 0 {
+		if err := setOption(ctx, engine, "Threads", p.config.Threads/p.config.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.config.HashMegabytes > 0 {
+		hash := p.config.HashMegabytes / p.config.Size
+		if hash < 1 {
+			hash = 1
+		}
+
+		if err := setOption(ctx, engine, "Hash", hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return engine, nil
+}
+
+// sets an engine option, engine is closed if it fails
+func setOption(ctx context.Context, engine Engine, name string, value int) error {
+	if err := engine.SetOption(ctx, name, strconv.Itoa(value)); err != nil {
+		engine.Close()
+		return fmt.Errorf("error setting engine %s option: %w", name, err)
+	}
+
+	return nil
+}
+
+// Close ends every engine of the pool, analyses in progress are stopped and fail with ErrClosed
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+
+	// engines are closed unlocked, so a restart waiting for the lock does not wait for them
+	engines := []Engine{}
+	for _, s := range p.slots {
+		if s.engine != nil {
+			engines = append(engines, s.engine)
+		}
+	}
+	p.mu.Unlock()
+
+	var err error
+	for _, engine := range engines {
+		if closeErr := engine.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,259 @@
+package enginepool
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"chenizz/internal/services/internal/search"
+	"chenizz/internal/services/internal/uci"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Engine = &uci.Client{}
+
+// engines that answer with the number of moves of the position as best move
+type fakeEngines struct {
+	mu       sync.Mutex
+	started  int
+	options  []map[string]string
+	running  int
+	maxRun   int
+	delay    time.Duration
+	startErr error
+
+	// engines crash analyzing positions after this move, crashes is how many times
+	crashAfter string
+	crashes    int
+}
+
+type fakeEngine struct {
+	engines *fakeEngines
+	options map[string]string
+	exited  bool
+
+	// held while analyzing, like uci.Client does, so Close waits for Analyze
+	busy sync.Mutex
+}
+
+func (f *fakeEngines) start(ctx context.Context) (Engine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+
+	f.started++
+	e := &fakeEngine{engines: f, options: map[string]string{}}
+	f.options = append(f.options, e.options)
+	return e, nil
+}
+
+func (e *fakeEngine) SetOption(ctx context.Context, name, value string) error {
+	e.options[name] = value
+	return nil
+}
+
+func (e *fakeEngine) Analyze(ctx context.Context, fen string, moves []string, limits search.Limits, onInfo func(uci.Info)) (uci.Analysis, error) {
+	e.busy.Lock()
+	defer e.busy.Unlock()
+
+	f := e.engines
+	f.mu.Lock()
+	if e.exited {
+		f.mu.Unlock()
+		return uci.Analysis{}, uci.ErrEngineExited
+	}
+
+	if len(moves) > 0 && moves[len(moves)-1] == f.crashAfter && f.crashes > 0 {
+		f.crashes--
+		e.exited = true
+		f.mu.Unlock()
+		return uci.Analysis{}, uci.ErrEngineExited
+	}
+
+	f.running++
+	if f.running > f.maxRun {
+		f.maxRun = f.running
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return uci.Analysis{}, ctx.Err()
+	}
+
+	return uci.Analysis{BestMove: fen + strconv.Itoa(len(moves))}, nil
+}
+
+func (e *fakeEngine) Close() error {
+	e.busy.Lock()
+	defer e.busy.Unlock()
+
+	e.engines.mu.Lock()
+	defer e.engines.mu.Unlock()
+
+	e.exited = true
+	return nil
+}
+
+func newPool(t *testing.T, engines *fakeEngines, config Config) *Pool {
+	p, err := New(context.Background(), engines.start, config)
+	assert.Nil(t, err)
+	t.Cleanup(func() { p.Close() })
+
+	return p
+}
+
+func bestMoves(r GameResult) []string {
+	moves := []string{}
+	for _, a := range r.Positions {
+		moves = append(moves, a.BestMove)
+	}
+
+	return moves
+}
+
+func Test_Pool_Analyze(t *testing.T) {
+	engines := &fakeEngines{delay: 10 * time.Millisecond}
+	p := newPool(t, engines, Config{Size: 3})
+
+	games := []Game{
+		{ID: "a", Moves: []string{"e2e4", "e7e5", "g1f3"}},
+		{ID: "b", FEN: "f", Moves: []string{"d2d4"}},
+		{ID: "c"},
+	}
+
+	var mu sync.Mutex
+	progress := map[string][]int{}
+	results := p.Analyze(context.Background(), games, func(pr Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress[pr.GameID] = append(progress[pr.GameID], pr.Analyzed)
+		assert.Equal(t, len(games[pr.GameID[0]-'a'].Moves)+1, pr.Total)
+	})
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, "a", results[0].ID)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, bestMoves(results[0]))
+	assert.Equal(t, []string{"f0", "f1"}, bestMoves(results[1]))
+	assert.Equal(t, []string{"0"}, bestMoves(results[2]))
+	for _, r := range results {
+		assert.Nil(t, r.Err)
+	}
+
+	assert.Equal(t, map[string][]int{"a": {1, 2, 3, 4}, "b": {1, 2}, "c": {1}}, progress)
+	assert.Equal(t, 3, engines.maxRun)
+	assert.Equal(t, 3, engines.started)
+}
+
+func Test_Pool_Resources(t *testing.T) {
+	engines := &fakeEngines{}
+	p := newPool(t, engines, Config{Size: 4, Threads: 8, HashMegabytes: 256})
+
+	assert.Equal(t, 4, p.Size())
+	for _, options := range engines.options {
+		assert.Equal(t, map[string]string{"Threads": "2", "Hash": "64"}, options)
+	}
+
+	// there are not threads for every engine
+	engines = &fakeEngines{}
+	p = newPool(t, engines, Config{Size: 4, Threads: 2})
+	assert.Equal(t, 2, p.Size())
+	assert.Equal(t, []map[string]string{{"Threads": "1"}, {"Threads": "1"}}, engines.options)
+}
+
+func Test_Pool_RestartsCrashedEngines(t *testing.T) {
+	engines := &fakeEngines{crashAfter: "e7e5", crashes: 1}
+	p := newPool(t, engines, Config{Size: 2})
+
+	results := p.Analyze(context.Background(), []Game{{ID: "a", Moves: []string{"e2e4", "e7e5", "g1f3"}}}, nil)
+
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, bestMoves(results[0]))
+	assert.Equal(t, 3, engines.started)
+}
+
+func Test_Pool_PositionCrashesEveryEngine(t *testing.T) {
+	engines := &fakeEngines{crashAfter: "e7e5", crashes: 100}
+	p := newPool(t, engines, Config{Size: 1, MaxRestarts: 2})
+
+	results := p.Analyze(context.Background(), []Game{
+		{ID: "a", Moves: []string{"e2e4", "e7e5"}},
+		{ID: "b", Moves: []string{"d2d4"}},
+	}, nil)
+
+	assert.ErrorIs(t, results[0].Err, uci.ErrEngineExited)
+	assert.Contains(t, results[0].Err.Error(), "after 2 moves")
+	assert.Equal(t, []string{"0", "1", ""}, bestMoves(results[0]))
+
+	// engine is restarted for next game
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, []string{"0", "1"}, bestMoves(results[1]))
+	assert.Equal(t, 3, engines.started)
+
+	// it can not be restarted anymore
+	results = p.Analyze(context.Background(), []Game{{ID: "c", Moves: []string{"e7e5"}}}, nil)
+	assert.ErrorContains(t, results[0].Err, "engine crashed 2 times")
+}
+
+func Test_Pool_Cancel(t *testing.T) {
+	engines := &fakeEngines{delay: time.Second}
+	p := newPool(t, engines, Config{Size: 2})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := p.Analyze(ctx, []Game{{ID: "a", Moves: make([]string, 10)}, {ID: "b"}}, nil)
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+	assert.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+}
+
+func Test_Pool_Errors(t *testing.T) {
+	engines := &fakeEngines{startErr: errors.New("no such file")}
+	_, err := New(context.Background(), engines.start, Config{Size: 2})
+	assert.ErrorContains(t, err, "error starting engine: no such file")
+
+	engines = &fakeEngines{}
+	p := newPool(t, engines, Config{Size: 1})
+	assert.Nil(t, p.Close())
+
+	results := p.Analyze(context.Background(), []Game{{ID: "a"}}, nil)
+	assert.ErrorIs(t, results[0].Err, ErrClosed)
+}
+
+func Test_Pool_CloseDuringAnalyze(t *testing.T) {
+	engines := &fakeEngines{delay: 5 * time.Second}
+	p := newPool(t, engines, Config{Size: 1})
+
+	results := make(chan []GameResult)
+	go func() {
+		results <- p.Analyze(context.Background(), []Game{{ID: "a", Moves: make([]string, 3)}}, nil)
+	}()
+
+	assert.Eventually(t, func() bool {
+		engines.mu.Lock()
+		defer engines.mu.Unlock()
+		return engines.running == 1
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+	assert.Nil(t, p.Close())
+	r := <-results
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, r[0].Err, ErrClosed)
+}
@@ -0,0 +1,337 @@
+package evaluation
+
+import (
+	"chenizz/internal/services/internal/chess"
+)
+
+type (
+	// Evaluator scores positions with material, piece-square tables, pawn structure,
+	// king safety and mobility, blending middlegame and endgame weights by game phase.
+	Evaluator struct {
+		weights Weights
+
+		// weights of every piece kind, built once from weights
+		values      [pieceKinds]Phased
+		tables      [pieceKinds]*Table
+		mobilities  [pieceKinds]Phased
+		hasMobility [pieceKinds]bool
+	}
+
+	// index of a piece type in pieceTypes
+	pieceKind int
+
+	// Term is the part of an evaluation due to one kind of feature,
+	// in centipawns from white point of view
+	Term struct {
+		Name       string `json:"name"`
+		Middlegame int    `json:"middlegame"`
+		Endgame    int    `json:"endgame"`
+
+		// middlegame and endgame values blended by game phase
+		Score int `json:"score"`
+	}
+
+	// Breakdown explains an evaluation term by term
+	Breakdown struct {
+		// from MaxPhase with every piece on board to 0 with only kings and pawns
+		Phase int    `json:"phase"`
+		Terms []Term `json:"terms"`
+
+		// sum of terms scores, in centipawns from white point of view
+		Total int `json:"total"`
+	}
+
+	// a piece on board
+	placed struct {
+		kind       pieceKind
+		square     chess.Square
+		file, rank int
+	}
+
+	// pieces of one color
+	side struct {
+		turn   string
+		pieces []placed
+		pawns  []placed
+		king   *placed
+	}
+)
+
+const (
+	TermMaterial      = "material"
+	TermPieceSquare   = "piece_square"
+	TermPawnStructure = "pawn_structure"
+	TermKingSafety    = "king_safety"
+	TermMobility      = "mobility"
+
+	// phase of a position with every piece of a game start
+	MaxPhase = 24
+)
+
+const (
+	pawn pieceKind = iota
+	knight
+	bishop
+	rook
+	queen
+	king
+
+	pieceKinds
+)
+
+// piece types written as white pieces, in the order they are evaluated
+var pieceTypes = [pieceKinds]chess.Piece{chess.WPawn, chess.WKnight, chess.WBishop, chess.WRook, chess.WQueen, chess.WKing}
+
+// black piece of every piece type
+var blackPieces = [pieceKinds]chess.Piece{chess.BPawn, chess.BKnight, chess.BBishop, chess.BRook, chess.BQueen, chess.BKing}
+
+// how much each piece type takes game away from endgame
+var phaseValues = [pieceKinds]int{knight: 1, bishop: 1, rook: 2, queen: 4}
+
+// NewEvaluator returns an evaluator that uses weights
+func NewEvaluator(weights Weights) *Evaluator {
+	e := &Evaluator{weights: weights}
+	e.values = [pieceKinds]Phased{
+		pawn: e.weights.Pawn, knight: e.weights.Knight, bishop: e.weights.Bishop,
+		rook: e.weights.Rook, queen: e.weights.Queen,
+	}
+	e.tables = [pieceKinds]*Table{
+		pawn: &e.weights.PieceSquare.Pawn, knight: &e.weights.PieceSquare.Knight,
+		bishop: &e.weights.PieceSquare.Bishop, rook: &e.weights.PieceSquare.Rook,
+		queen: &e.weights.PieceSquare.Queen, king: &e.weights.PieceSquare.King,
+	}
+	e.mobilities = [pieceKinds]Phased{
+		knight: e.weights.KnightMobility, bishop: e.weights.BishopMobility,
+		rook: e.weights.RookMobility, queen: e.weights.QueenMobility,
+	}
+	e.hasMobility = [pieceKinds]bool{knight: true, bishop: true, rook: true, queen: true}
+
+	return e
+}
+
+// Evaluate returns the score of board position in centipawns from the point of view
+// of its side to move, so it can be used as a search evaluator.
+func (e *Evaluator) Evaluate(board *chess.Board) int {
+	total := e.Breakdown(board).Total
+	if board.Turn == "b" {
+		return -total
+	}
+
+	return total
+}
+
+// Breakdown returns the evaluation of board position with the score of each term
+func (e *Evaluator) Breakdown(board *chess.Board) Breakdown {
+	white, black := newSide(board, "w"), newSide(board, "b")
+	phase := gamePhase(white, black)
+
+	terms := []struct {
+		name  string
+		score func(board *chess.Board, us, them *side) Phased
+	}{
+		{TermMaterial, e.material},
+		{TermPieceSquare, e.pieceSquare},
+		{TermPawnStructure, e.pawnStructure},
+		{TermKingSafety, e.kingSafety},
+		{TermMobility, e.mobility},
+	}
+
+	b := Breakdown{Phase: phase, Terms: make([]Term, 0, len(terms))}
+	for _, t := range terms {
+		w, bl := t.score(board, white, black), t.score(board, black, white)
+		term := Term{
+			Name:       t.name,
+			Middlegame: w.Middlegame - bl.Middlegame,
+			Endgame:    w.Endgame - bl.Endgame,
+		}
+		term.Score = (term.Middlegame*phase + term.Endgame*(MaxPhase-phase)) / MaxPhase
+
+		b.Terms = append(b.Terms, term)
+		b.Total += term.Score
+	}
+
+	return b
+}
+
+// Term returns the term called name, zero if there is not any
+func (b Breakdown) Term(name string) Term {
+	for _, t := range b.Terms {
+		if t.Name == name {
+			return t
+		}
+	}
+
+	return Term{}
+}
+
+func (e *Evaluator) material(_ *chess.Board, us, _ *side) Phased {
+	score := Phased{}
+	for _, p := range us.pieces {
+		score.add(e.values[p.kind], 1)
+	}
+
+	return score
+}
+
+func (e *Evaluator) pieceSquare(_ *chess.Board, us, _ *side) Phased {
+	score := Phased{}
+	for _, p := range us.pieces {
+		i := us.tableIndex(p)
+		t := e.tables[p.kind]
+		score.add(Phased{t.Middlegame[i], t.Endgame[i]}, 1)
+	}
+
+	return score
+}
+
+// doubled, isolated and passed pawns
+func (e *Evaluator) pawnStructure(_ *chess.Board, us, them *side) Phased {
+	files := us.pawnFiles()
+	score := Phased{}
+	for _, count := range files {
+		if count > 1 {
+			score.add(e.weights.DoubledPawn, count-1)
+		}
+	}
+
+	for _, p := range us.pawns {
+		if (p.file == 0 || files[p.file-1] == 0) && (p.file == 7 || files[p.file+1] == 0) {
+			score.add(e.weights.IsolatedPawn, 1)
+		}
+
+		if us.isPassed(p, them) {
+			score.add(e.weights.PassedPawn[us.relativeRank(p.rank)], 1)
+		}
+	}
+
+	return score
+}
+
+// pawns sheltering king and opponent pieces attacking squares around it
+func (e *Evaluator) kingSafety(board *chess.Board, us, them *side) Phased {
+	score := Phased{}
+	if us.king == nil {
+		return score
+	}
+
+	k := us.king
+	for _, p := range us.pawns {
+		ahead := us.relativeRank(p.rank) - us.relativeRank(k.rank)
+		if abs(p.file-k.file) <= 1 && ahead >= 1 && ahead <= 2 {
+			score.add(e.weights.PawnShield, 1)
+		}
+	}
+
+	zone := board.AttacksFrom(k.square) | 1<<k.square
+	for zone != 0 {
+		score.add(e.weights.KingZoneAttack, board.AttackersTo(zone.Pop(), them.turn).Count())
+	}
+
+	return score
+}
+
+// squares attacked by pieces that are empty or taken by opponent pieces
+func (e *Evaluator) mobility(board *chess.Board, us, _ *side) Phased {
+	own := board.PiecesOf(us.turn)
+	score := Phased{}
+	for _, p := range us.pieces {
+		if !e.hasMobility[p.kind] {
+			continue
+		}
+
+		score.add(e.mobilities[p.kind], (board.AttacksFrom(p.square) &^ own).Count())
+	}
+
+	return score
+}
+
+func newSide(board *chess.Board, turn string) *side {
+	s := &side{turn: turn}
+	pieces := pieceTypes
+	if turn == "b" {
+		pieces = blackPieces
+	}
+
+	for kind, piece := range pieces {
+		for squares := board.Pieces(piece); squares != 0; {
+			square := squares.Pop()
+			p := placed{kind: pieceKind(kind), square: square, file: square.File(), rank: square.Rank()}
+			s.pieces = append(s.pieces, p)
+
+			if p.kind == pawn {
+				s.pawns = append(s.pawns, p)
+			}
+		}
+	}
+
+	for i := range s.pieces {
+		if s.pieces[i].kind == king {
+			s.king = &s.pieces[i]
+		}
+	}
+
+	return s
+}
+
+func gamePhase(white, black *side) int {
+	phase := 0
+	for _, s := range []*side{white, black} {
+		for _, p := range s.pieces {
+			phase += phaseValues[p.kind]
+		}
+	}
+
+	if phase > MaxPhase {
+		return MaxPhase
+	}
+
+	return phase
+}
+
+// pawns by file
+func (s *side) pawnFiles() [8]int {
+	files := [8]int{}
+	for _, p := range s.pawns {
+		files[p.file]++
+	}
+
+	return files
+}
+
+// returns true if no opponent pawn can stop or capture p on its way to promotion
+func (s *side) isPassed(p placed, them *side) bool {
+	for _, o := range them.pawns {
+		if abs(o.file-p.file) <= 1 && s.relativeRank(o.rank) > s.relativeRank(p.rank) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rank counted from side first rank
+func (s *side) relativeRank(rank int) int {
+	if s.turn == "b" {
+		return 7 - rank
+	}
+
+	return rank
+}
+
+// index of p square in a piece-square table, mirrored for black
+func (s *side) tableIndex(p placed) int {
+	return (7-s.relativeRank(p.rank))*8 + p.file
+}
+
+func (p *Phased) add(w Phased, times int) {
+	p.Middlegame += w.Middlegame * times
+	p.Endgame += w.Endgame * times
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,88 @@
+package evaluation
+
+import (
+	"testing"
+
+	"chenizz/internal/services/internal/chess"
+	"chenizz/internal/services/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ search.Evaluator = &Evaluator{}
+
+func breakdownOf(t *testing.T, fen string) Breakdown {
+	board := chess.Board{}
+	err := board.TranslateFEN(fen)
+	assert.Nil(t, err)
+
+	return NewEvaluator(DefaultWeights()).Breakdown(&board)
+}
+
+func Test_Breakdown_StartingPosition(t *testing.T) {
+	board := chess.NewBoard()
+	b := NewEvaluator(DefaultWeights()).Breakdown(&board)
+
+	assert.Equal(t, MaxPhase, b.Phase)
+	assert.Equal(t, 0, b.Total)
+	for _, name := range []string{TermMaterial, TermPieceSquare, TermPawnStructure, TermKingSafety, TermMobility} {
+		assert.Equal(t, Term{Name: name}, b.Term(name))
+	}
+}
+
+func Test_Breakdown_Material(t *testing.T) {
+	b := breakdownOf(t, "rnb1kbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+
+	assert.Equal(t, 20, b.Phase)
+	assert.Equal(t, Term{Name: TermMaterial, Middlegame: 900, Endgame: 940, Score: 906}, b.Term(TermMaterial))
+	assert.Equal(t, Term{}, b.Term("unknown"))
+}
+
+func Test_Breakdown_PawnStructure(t *testing.T) {
+	// doubled and isolated pawns, all of them passed
+	b := breakdownOf(t, "4k3/8/8/8/8/P7/P1P5/4K3 w - - 0 1")
+	assert.Equal(t, 0, b.Phase)
+	assert.Equal(t, Term{Name: TermPawnStructure, Middlegame: -25, Endgame: -30, Score: -30}, b.Term(TermPawnStructure))
+
+	// pawns on adjacent files block each other
+	b = breakdownOf(t, "4k3/3p4/8/4P3/8/8/8/4K3 w - - 0 1")
+	assert.Equal(t, 0, b.Term(TermPawnStructure).Score)
+
+	b = breakdownOf(t, "4k3/8/8/1p2P3/8/8/8/4K3 w - - 0 1")
+	assert.Equal(t, 45-15-(25-15), b.Term(TermPawnStructure).Score)
+}
+
+func Test_Breakdown_KingSafety(t *testing.T) {
+	b := breakdownOf(t, "r3k3/8/8/8/8/8/5PPP/R5K1 w - - 0 1")
+	assert.Equal(t, 30, b.Term(TermKingSafety).Middlegame)
+
+	// rook attacks king and f1
+	b = breakdownOf(t, "4k3/8/8/8/8/8/5PPP/r5K1 w - - 0 1")
+	assert.Equal(t, Term{Name: TermKingSafety, Middlegame: 30 - 2*8, Endgame: -2 * 2, Score: -2}, b.Term(TermKingSafety))
+}
+
+func Test_Breakdown_Mobility(t *testing.T) {
+	b := breakdownOf(t, "4k3/8/8/8/4N3/8/8/4K3 w - - 0 1")
+	assert.Equal(t, 8*4, b.Term(TermMobility).Score)
+
+	// own pieces take squares away
+	b = breakdownOf(t, "4k3/8/8/8/4N3/8/3P4/4K3 w - - 0 1")
+	assert.Equal(t, 7*4, b.Term(TermMobility).Score)
+}
+
+func Test_Evaluate(t *testing.T) {
+	evaluator := NewEvaluator(DefaultWeights())
+	board := chess.Board{}
+	err := board.TranslateFEN("4k3/8/8/8/8/P7/P1P5/4K3 w - - 0 1")
+	assert.Nil(t, err)
+	white := evaluator.Evaluate(&board)
+
+	err = board.TranslateFEN("4k3/8/8/8/8/P7/P1P5/4K3 b - - 0 1")
+	assert.Nil(t, err)
+	assert.Equal(t, -white, evaluator.Evaluate(&board))
+
+	// same position with colors swapped
+	err = board.TranslateFEN("4k3/p1p5/p7/8/8/8/8/4K3 b - - 0 1")
+	assert.Nil(t, err)
+	assert.Equal(t, white, evaluator.Evaluate(&board))
+	assert.Greater(t, white, 0)
+}
@@ -0,0 +1,112 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type (
+	// Phased is a weight with a value for middlegame and another for endgame,
+	// evaluation blends them by game phase. penalties are negative values.
+	Phased struct {
+		Middlegame int `json:"middlegame"`
+		Endgame    int `json:"endgame"`
+	}
+
+	// Table is a bonus by square of a piece type from white point of view,
+	// squares are written from a8 to h1 like the board is seen by white.
+	// black pieces use it mirrored.
+	Table struct {
+		Middlegame [64]int `json:"middlegame"`
+		Endgame    [64]int `json:"endgame"`
+	}
+
+	PieceSquareTables struct {
+		Pawn   Table `json:"pawn"`
+		Knight Table `json:"knight"`
+		Bishop Table `json:"bishop"`
+		Rook   Table `json:"rook"`
+		Queen  Table `json:"queen"`
+		King   Table `json:"king"`
+	}
+
+	// Weights of every evaluation term in centipawns
+	Weights struct {
+		Pawn   Phased `json:"pawn"`
+		Knight Phased `json:"knight"`
+		Bishop Phased `json:"bishop"`
+		Rook   Phased `json:"rook"`
+		Queen  Phased `json:"queen"`
+
+		PieceSquare PieceSquareTables `json:"piece_square"`
+
+		// for every pawn on a file after the first one
+		DoubledPawn Phased `json:"doubled_pawn"`
+
+		// for every pawn without own pawns on adjacent files
+		IsolatedPawn Phased `json:"isolated_pawn"`
+
+		// for a pawn without opponent pawns in front of it on its file and adjacent ones,
+		// indexed by its rank counted from its own side, 0 is first rank
+		PassedPawn [8]Phased `json:"passed_pawn"`
+
+		// for every own pawn in the two ranks in front of king, on its file or adjacent ones
+		PawnShield Phased `json:"pawn_shield"`
+
+		// for every opponent piece attacking king square or squares next to it
+		KingZoneAttack Phased `json:"king_zone_attack"`
+
+		// for every square a piece attacks that is not taken by an own piece
+		KnightMobility Phased `json:"knight_mobility"`
+		BishopMobility Phased `json:"bishop_mobility"`
+		RookMobility   Phased `json:"rook_mobility"`
+		QueenMobility  Phased `json:"queen_mobility"`
+	}
+)
+
+// DefaultWeights returns the weights evaluation uses if none are given
+func DefaultWeights() Weights {
+	return Weights{
+		Pawn:   Phased{100, 120},
+		Knight: Phased{320, 300},
+		Bishop: Phased{330, 320},
+		Rook:   Phased{500, 530},
+		Queen:  Phased{900, 940},
+
+		PieceSquare: defaultPieceSquareTables,
+
+		DoubledPawn:  Phased{-10, -20},
+		IsolatedPawn: Phased{-10, -15},
+		PassedPawn: [8]Phased{
+			{0, 0}, {5, 10}, {5, 15}, {10, 25}, {20, 45}, {35, 75}, {60, 120}, {0, 0},
+		},
+
+		PawnShield:     Phased{10, 0},
+		KingZoneAttack: Phased{-8, -2},
+
+		KnightMobility: Phased{4, 4},
+		BishopMobility: Phased{5, 5},
+		RookMobility:   Phased{2, 4},
+		QueenMobility:  Phased{1, 2},
+	}
+}
+
+// LoadWeights reads weights from a JSON file at path. weights missing in the file
+// keep their default value, unknown ones are an error so typos are not ignored.
+func LoadWeights(path string) (Weights, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Weights{}, fmt.Errorf("error calling os.Open: %w", err)
+	}
+	defer f.Close()
+
+	weights := DefaultWeights()
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&weights); err != nil {
+		return Weights{}, fmt.Errorf("invalid weights file %s: %w", path, err)
+	}
+
+	return weights, nil
+}
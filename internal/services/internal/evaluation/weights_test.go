@@ -0,0 +1,25 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadWeights(t *testing.T) {
+	weights, err := LoadWeights("testdata/weights.json")
+	assert.Nil(t, err)
+
+	expected := DefaultWeights()
+	expected.Pawn = Phased{90, 130}
+	expected.DoubledPawn = Phased{-25, -40}
+	assert.Equal(t, expected, weights)
+}
+
+func Test_LoadWeights_Errors(t *testing.T) {
+	_, err := LoadWeights("testdata/unknown.json")
+	assert.ErrorContains(t, err, `unknown field "pawns"`)
+
+	_, err = LoadWeights("testdata/missing.json")
+	assert.ErrorContains(t, err, "error calling os.Open: ")
+}
@@ -0,0 +1,260 @@
+package pgn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+
+	// quoted string with escapes resolved
+	tokenString
+
+	// tag names, moves, move numbers and results other than "*"
+	tokenSymbol
+
+	tokenPeriod
+	tokenAsterisk
+	tokenOpenBracket
+	tokenCloseBracket
+	tokenOpenParen
+	tokenCloseParen
+
+	// numeric annotation glyph like "$1", value has its number.
+	// suffixes like "!" or "?!" are read as their NAG.
+	tokenNAG
+
+	// text of a brace or rest of line comment
+	tokenComment
+
+	// character that does not start any token
+	tokenInvalid
+)
+
+// token of a PGN, line and column are where it starts, both counted from 1
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+// lexer splits PGN text in tokens as the PGN standard describes them
+type lexer struct {
+	r      *bufio.Reader
+	line   int
+	column int
+
+	// position before the last rune read, to unread it
+	prevLine, prevColumn int
+
+	// error reading r, other than io.EOF
+	err error
+}
+
+// NAGs of move suffix annotations
+var suffixNAGs = map[string]string{"!": "1", "?": "2", "!!": "3", "??": "4", "!?": "5", "?!": "6"}
+
+func newLexer(r io.Reader) *lexer {
+	return &lexer{r: bufio.NewReader(r), line: 1, column: 0}
+}
+
+func (l *lexer) read() (rune, bool) {
+	c, _, err := l.r.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			l.err = err
+		}
+		return 0, false
+	}
+
+	l.prevLine, l.prevColumn = l.line, l.column
+	if c == '\n' {
+		l.line++
+		l.column = 0
+	} else {
+		l.column++
+	}
+
+	return c, true
+}
+
+func (l *lexer) unread() {
+	l.r.UnreadRune()
+	l.line, l.column = l.prevLine, l.prevColumn
+}
+
+// returns the next token, tokenEOF at the end of input
+func (l *lexer) next() token {
+	for {
+		c, ok := l.read()
+		if !ok {
+			return token{kind: tokenEOF, line: l.line, column: l.column + 1}
+		}
+
+		// lines starting with "%" are ignored
+		if c == '%' && l.column == 1 {
+			l.skipLine()
+			continue
+		}
+
+		if unicode.IsSpace(c) {
+			continue
+		}
+
+		t := token{line: l.line, column: l.column}
+		switch c {
+		case '"':
+			return l.readString(t)
+		case '{':
+			return l.readBraceComment(t)
+		case ';':
+			t.kind, t.value = tokenComment, strings.TrimSpace(l.skipLine())
+		case '.':
+			t.kind = tokenPeriod
+		case '*':
+			t.kind = tokenAsterisk
+		case '[':
+			t.kind = tokenOpenBracket
+		case ']':
+			t.kind = tokenCloseBracket
+		case '(':
+			t.kind = tokenOpenParen
+		case ')':
+			t.kind = tokenCloseParen
+		case '$':
+			t.kind, t.value = tokenNAG, l.readWhile(unicode.IsDigit)
+			if t.value == "" {
+				t.kind, t.value = tokenInvalid, "$"
+			}
+		case '!', '?':
+			suffix := string(c) + l.readWhile(func(r rune) bool { return r == '!' || r == '?' })
+			t.kind, t.value = tokenNAG, suffixNAGs[suffix]
+			if t.value == "" {
+				t.kind, t.value = tokenInvalid, suffix
+			}
+		default:
+			// Crazyhouse pawn drops can be written without piece letter, like "@e4"
+			if !isSymbolStart(c) && c != '@' {
+				t.kind, t.value = tokenInvalid, string(c)
+				return t
+			}
+
+			t.kind, t.value = tokenSymbol, string(c)+l.readWhile(isSymbolContinuation)
+		}
+
+		return t
+	}
+}
+
+// reads a string after its opening quote, tokenInvalid if it is not closed in the same line
+func (l *lexer) readString(t token) token {
+	str := strings.Builder{}
+	for {
+		c, ok := l.read()
+		if !ok || c == '\n' {
+			t.kind, t.value = tokenInvalid, `"`
+			return t
+		}
+
+		switch c {
+		case '"':
+			t.kind, t.value = tokenString, str.String()
+			return t
+		case '\\':
+			escaped, ok := l.read()
+			if !ok || (escaped != '"' && escaped != '\\') {
+				t.kind, t.value = tokenInvalid, `"`
+				return t
+			}
+			c = escaped
+		}
+
+		str.WriteRune(c)
+	}
+}
+
+// reads a comment after its opening brace, tokenInvalid if it is never closed
+func (l *lexer) readBraceComment(t token) token {
+	str := strings.Builder{}
+	for {
+		c, ok := l.read()
+		if !ok {
+			t.kind, t.value = tokenInvalid, "{"
+			return t
+		}
+
+		if c == '}' {
+			t.kind, t.value = tokenComment, strings.TrimSpace(str.String())
+			return t
+		}
+
+		str.WriteRune(c)
+	}
+}
+
+// returns the rest of the current line, its end included
+func (l *lexer) skipLine() string {
+	return l.readWhile(func(r rune) bool { return r != '\n' })
+}
+
+func (l *lexer) readWhile(accept func(rune) bool) string {
+	str := strings.Builder{}
+	for {
+		c, ok := l.read()
+		if !ok {
+			return str.String()
+		}
+
+		if !accept(c) {
+			l.unread()
+			return str.String()
+		}
+
+		str.WriteRune(c)
+	}
+}
+
+func isSymbolStart(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
+
+// symbols can have "@" too, so Crazyhouse drops like "N@f3" are read as a single move
+func isSymbolContinuation(c rune) bool {
+	return isSymbolStart(c) || strings.ContainsRune("_+#=:-/@", c)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	case tokenComment:
+		return "comment"
+	case tokenNAG:
+		return "$" + t.value
+	case tokenInvalid:
+		return fmt.Sprintf("invalid character %q", t.value)
+	case tokenPeriod:
+		return `"."`
+	case tokenAsterisk:
+		return `"*"`
+	case tokenOpenBracket:
+		return `"["`
+	case tokenCloseBracket:
+		return `"]"`
+	case tokenOpenParen:
+		return `"("`
+	case tokenCloseParen:
+		return `")"`
+	}
+
+	return fmt.Sprintf("%q", t.value)
+}
@@ -0,0 +1,67 @@
+package pgn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lexAll(input string) []token {
+	l := newLexer(strings.NewReader(input))
+	var tokens []token
+	for {
+		t := l.next()
+		if t.kind == tokenEOF {
+			return tokens
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+func TestLexerTokens(t *testing.T) {
+	assert := assert.New(t)
+	input := `[Event "A \"quoted\" \\ name"]
+% ignored escape line
+1.e4 $1 e5!? {a
+comment} 2... Nf3 ; rest of line
+(2. d4) N@f3 @e4 *`
+
+	expected := []token{
+		{tokenOpenBracket, "", 1, 1},
+		{tokenSymbol, "Event", 1, 2},
+		{tokenString, `A "quoted" \ name`, 1, 8},
+		{tokenCloseBracket, "", 1, 30},
+		{tokenSymbol, "1", 3, 1},
+		{tokenPeriod, "", 3, 2},
+		{tokenSymbol, "e4", 3, 3},
+		{tokenNAG, "1", 3, 6},
+		{tokenSymbol, "e5", 3, 9},
+		{tokenNAG, "5", 3, 11},
+		{tokenComment, "a\ncomment", 3, 14},
+		{tokenSymbol, "2", 4, 10},
+		{tokenPeriod, "", 4, 11},
+		{tokenPeriod, "", 4, 12},
+		{tokenPeriod, "", 4, 13},
+		{tokenSymbol, "Nf3", 4, 15},
+		{tokenComment, "rest of line", 4, 19},
+		{tokenOpenParen, "", 5, 1},
+		{tokenSymbol, "2", 5, 2},
+		{tokenPeriod, "", 5, 3},
+		{tokenSymbol, "d4", 5, 5},
+		{tokenCloseParen, "", 5, 7},
+		{tokenSymbol, "N@f3", 5, 9},
+		{tokenSymbol, "@e4", 5, 14},
+		{tokenAsterisk, "", 5, 18},
+	}
+
+	assert.Equal(expected, lexAll(input))
+}
+
+func TestLexerInvalidTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]token{{tokenInvalid, `"`, 1, 1}, {tokenSymbol, "e4", 2, 1}}, lexAll("\"not closed\ne4"))
+	assert.Equal([]token{{tokenSymbol, "e4", 1, 1}, {tokenInvalid, "{", 1, 4}}, lexAll("e4 {never closed"))
+	assert.Equal([]token{{tokenInvalid, "&", 1, 1}, {tokenInvalid, "$", 1, 3}, {tokenInvalid, "!!!", 1, 5}}, lexAll("& $ !!!"))
+}
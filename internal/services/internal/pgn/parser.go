@@ -0,0 +1,347 @@
+package pgn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"chenizz/internal/services/internal/chess"
+)
+
+type (
+	// ParseError is a problem found in a game of a PGN, line and column are counted from 1
+	ParseError struct {
+		// number of the game in the input, first one is 1
+		Game   int
+		Line   int
+		Column int
+		Err    error
+	}
+
+	// ParseErrors are the errors of every game that could not be parsed, in input order
+	ParseErrors []*ParseError
+
+	// parser reads games from lexer tokens following the PGN standard grammar
+	parser struct {
+		lex *lexer
+		tok token
+
+		// token before tok, to tell tag names from symbols of move text
+		prev token
+
+		// games read so far, the one being parsed included
+		games int
+
+		// raw move text of the game being parsed
+		moveText []string
+	}
+)
+
+const (
+	resultUnknown = "*"
+
+	// highest NAG of the PGN standard
+	maxNAG = 255
+)
+
+var errNoMove = errors.New("there is no move before the variation")
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("game %d, line %d, column %d: %v", e.Game, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// ParseGames reads every game of a PGN following its standard: tag pairs, move text with
+// move numbers, comments, NAGs, variations and result, and lines starting with "%" are ignored.
+// moves of main line and variations are checked against the rules of Variant tag.
+// a game with errors is skipped and parsing goes on with the next one, so returned error
+// is ParseErrors with the line and column of the problem of every skipped game.
+func ParseGames(r io.Reader) ([]PGN, error) {
+	reader := NewReader(r)
+
+	var games []PGN
+	var errs ParseErrors
+	for {
+		game, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			errs = append(errs, parseErr)
+			continue
+		}
+
+		if err != nil {
+			return games, err
+		}
+
+		games = append(games, game)
+	}
+
+	if errs != nil {
+		return games, errs
+	}
+
+	return games, nil
+}
+
+func newParser(r io.Reader) *parser {
+	p := &parser{lex: newLexer(r)}
+	p.next()
+	return p
+}
+
+func (p *parser) next() {
+	p.prev = p.tok
+	p.tok = p.lex.next()
+}
+
+// returns io.EOF if there are no more games, or a *ParseError if game is not valid.
+// after an error tokens are skipped until the next game starts.
+func (p *parser) parseGame() (PGN, error) {
+	if p.tok.kind == tokenEOF {
+		return PGN{}, io.EOF
+	}
+
+	p.games++
+	p.moveText = nil
+	game := PGN{}
+
+	if err := p.parseTags(&game); err != nil {
+		p.skipGame(true)
+		return PGN{}, err
+	}
+
+	start := p.tok
+	board, err := game.StartingBoard()
+	if err != nil {
+		p.skipGame(false)
+		return PGN{}, p.errorAt(start, err)
+	}
+
+	game.Tree = &Node{FEN: board.FEN()}
+	result, err := p.parseMoves(&board, game.Tree, 0)
+	if err != nil {
+		p.skipGame(false)
+		return PGN{}, err
+	}
+
+	if game.Result == "" {
+		game.Result = result
+	}
+
+	game.GamePlainText = strings.Join(p.moveText, " ")
+	game.UCIFormatMoves = board.MovesHistory
+	return game, nil
+}
+
+// reads tag pairs like [Event "Rated Blitz game"] until move text starts
+func (p *parser) parseTags(game *PGN) error {
+	for p.tok.kind == tokenOpenBracket {
+		p.next()
+		if p.tok.kind != tokenSymbol {
+			return p.unexpected("tag name")
+		}
+		name := p.tok.value
+
+		p.next()
+		if p.tok.kind != tokenString {
+			return p.unexpected("tag value")
+		}
+		value := p.tok.value
+
+		p.next()
+		if p.tok.kind != tokenCloseBracket {
+			return p.unexpected(`"]"`)
+		}
+		p.next()
+
+		insertHeaderInPGN(game, name, value)
+	}
+
+	return nil
+}
+
+// reads move text playing its moves on board until the game result, or until the closing
+// parenthesis if depth is not 0. moves are added as children of node, and variations of a move
+// as siblings of it. game ends without result if next game tags or input end come first.
+func (p *parser) parseMoves(board *chess.Board, node *Node, depth int) (string, error) {
+	// last move of the line and its parent, variations start from them
+	last, parent := node, (*Node)(nil)
+
+	// comments before the first move of a variation
+	var starting []string
+
+	// comments left when a line ends without moves belong to node
+	defer func() { node.Comments = append(node.Comments, starting...) }()
+
+	for {
+		t := p.tok
+		switch t.kind {
+		case tokenEOF, tokenOpenBracket:
+			if depth > 0 {
+				return "", p.unexpected(`")"`)
+			}
+			return resultUnknown, nil
+
+		case tokenAsterisk:
+			if depth > 0 {
+				return "", p.unexpected("move")
+			}
+			p.addMoveText(resultUnknown)
+			p.next()
+			return resultUnknown, nil
+
+		case tokenPeriod:
+			p.addMoveText(".")
+			p.next()
+
+		case tokenNAG:
+			p.addMoveText("$" + t.value)
+			p.next()
+
+			nag, err := strconv.Atoi(t.value)
+			if err != nil || nag > maxNAG {
+				return "", p.errorAt(t, fmt.Errorf("NAG $%s is not valid", t.value))
+			}
+			last.NAGs = append(last.NAGs, nag)
+
+		case tokenComment:
+			p.addMoveText("{" + t.value + "}")
+			p.next()
+
+			// line breaks of a comment are only formatting
+			comment := strings.Join(strings.Fields(t.value), " ")
+			if last == node && depth > 0 {
+				starting = append(starting, comment)
+				continue
+			}
+			last.Comments = append(last.Comments, comment)
+
+		case tokenOpenParen:
+			if parent == nil {
+				return "", p.errorAt(t, errNoMove)
+			}
+			p.addMoveText("(")
+			p.next()
+
+			// board is only copied when a variation starts, undoing last move of the line
+			variation := board.Clone()
+			variation.UnmakeMove()
+			if _, err := p.parseMoves(&variation, parent, depth+1); err != nil {
+				return "", err
+			}
+
+		case tokenCloseParen:
+			if depth == 0 {
+				return "", p.unexpected("move")
+			}
+			p.addMoveText(")")
+			p.next()
+			return "", nil
+
+		case tokenSymbol:
+			p.addMoveText(t.value)
+			p.next()
+
+			if isResult(t.value) {
+				if depth > 0 {
+					return "", p.errorAt(t, fmt.Errorf("result %s inside a variation", t.value))
+				}
+				return t.value, nil
+			}
+
+			if isMoveNumber(t.value) {
+				continue
+			}
+
+			m, err := board.ParseSAN(t.value)
+			if err != nil {
+				return "", p.errorAt(t, fmt.Errorf("error calling board.ParseSAN: %w", err))
+			}
+
+			child := &Node{SAN: board.SAN(m), StartingComments: starting}
+			starting = nil
+
+			board.Play(m)
+			child.UCI = board.MovesHistory[len(board.MovesHistory)-1]
+			child.FEN = board.FEN()
+
+			last.Children = append(last.Children, child)
+			last, parent = child, last
+
+		default:
+			return "", p.unexpected("move")
+		}
+	}
+}
+
+// skips tokens until the game result or the tags of the next game.
+// tags are the next game ones only after some move text, since an error
+// found in tags leaves the rest of them in the same game.
+func (p *parser) skipGame(inTags bool) {
+	sawMoveText := !inTags
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.tok.kind == tokenOpenBracket && sawMoveText:
+			return
+		case p.tok.kind == tokenAsterisk, p.tok.kind == tokenSymbol && isResult(p.tok.value):
+			p.next()
+			return
+		case p.tok.kind == tokenOpenBracket, p.tok.kind == tokenCloseBracket, p.tok.kind == tokenString,
+			p.tok.kind == tokenSymbol && p.prev.kind == tokenOpenBracket:
+		default:
+			sawMoveText = true
+		}
+
+		p.next()
+	}
+}
+
+func (p *parser) addMoveText(text string) {
+	// periods are glued to their move number, like "1." or "1..."
+	if text == "." && len(p.moveText) > 0 {
+		p.moveText[len(p.moveText)-1] += text
+		return
+	}
+
+	p.moveText = append(p.moveText, text)
+}
+
+func (p *parser) unexpected(expected string) *ParseError {
+	return p.errorAt(p.tok, fmt.Errorf("expected %s, got %s", expected, p.tok))
+}
+
+func (p *parser) errorAt(t token, err error) *ParseError {
+	return &ParseError{Game: p.games, Line: t.line, Column: t.column, Err: err}
+}
+
+func isResult(symbol string) bool {
+	return symbol == "1-0" || symbol == "0-1" || symbol == "1/2-1/2"
+}
+
+func isMoveNumber(symbol string) bool {
+	for _, c := range symbol {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
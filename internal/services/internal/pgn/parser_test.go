@@ -0,0 +1,201 @@
+package pgn
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGames(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Annotated game"]
+[White "Player, \"The\" One"]
+[Black "Player Two"]
+[Result "1-0"]
+
+1. e4 {best by test} e5 2. Nf3 $1 (2. f4 exf4 (2... d5) 3. Nf3) 2... Nc6!? ; italian
+3. Bc4 Bc5 1-0
+
+[Event "Second game"]
+[Variant "Crazyhouse"]
+
+1. e4 d5 2. exd5 Qxd5 3. Nc3 Qa5 4. P@d5 *
+`
+
+	// Act
+	pgns, err := ParseGames(strings.NewReader(games))
+
+	// Assert
+	assert.Nil(err)
+	assert.Len(pgns, 2)
+
+	assert.Equal("Annotated game", pgns[0].Event)
+	assert.Equal(`Player, "The" One`, pgns[0].White)
+	assert.Equal("1-0", pgns[0].Result)
+	assert.Equal([]string{"e2e4", "e7e5", "g1f3", "b8c6", "f1c4", "f8c5"}, pgns[0].UCIFormatMoves)
+	assert.Equal("1. e4 {best by test} e5 2. Nf3 $1 ( 2. f4 exf4 ( 2... d5 ) 3. Nf3 ) 2... Nc6 $5 {italian} 3. Bc4 Bc5 1-0", pgns[0].GamePlainText)
+
+	assert.Equal("Crazyhouse", pgns[1].Variant)
+	assert.Equal("*", pgns[1].Result)
+	assert.Equal([]string{"e2e4", "d7d5", "e4d5", "d8d5", "b1c3", "d5a5", "P@d5"}, pgns[1].UCIFormatMoves)
+}
+
+func TestParseGamesWithErrors(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Illegal move"]
+
+1. e4 e5 2. Ke3 Nc6 1-0
+
+[Event "Good game"]
+
+1. d4 d5 1/2-1/2
+
+[Event "Illegal variation"
+[Site "no closing bracket"]
+
+1. e4 e5 0-1
+
+[Event "Illegal move in variation"]
+
+1. e4 (1. d4 Ke3) e5 *
+
+[Event "No result before next game"]
+
+1. c4
+
+[Event "Bad FEN"]
+[FEN "8/8/8/8/8/8/8/8 w - - 0 1"]
+
+1. e4 *
+
+[Event "Unclosed variation"]
+
+1. e4 (1. d4 d5`
+
+	// Act
+	pgns, err := ParseGames(strings.NewReader(games))
+
+	// Assert
+	assert.Len(pgns, 2)
+	assert.Equal("Good game", pgns[0].Event)
+	assert.Equal([]string{"d2d4", "d7d5"}, pgns[0].UCIFormatMoves)
+	assert.Equal("No result before next game", pgns[1].Event)
+	assert.Equal("*", pgns[1].Result)
+	assert.Equal([]string{"c2c4"}, pgns[1].UCIFormatMoves)
+
+	var parseErrors ParseErrors
+	assert.True(errors.As(err, &parseErrors))
+	assert.Len(parseErrors, 5)
+
+	assert.Equal("game 1, line 3, column 13: error calling board.ParseSAN: Ke3 is not a legal move", parseErrors[0].Error())
+	assert.Equal(`game 3, line 10, column 1: expected "]", got "["`, parseErrors[1].Error())
+	assert.Equal("game 4, line 16, column 14: error calling board.ParseSAN: Ke3 is not a legal move", parseErrors[2].Error())
+	assert.Equal(6, parseErrors[3].Game)
+	assert.Equal(25, parseErrors[3].Line)
+	assert.Contains(parseErrors[3].Error(), "error calling board.TranslateFEN")
+	assert.Equal(`game 7, line 29, column 16: expected ")", got end of input`, parseErrors[4].Error())
+}
+
+func TestParseGamesUnexpectedTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseGames(strings.NewReader("1. e4 ) e5 *"))
+	assert.EqualError(err, `game 1, line 1, column 7: expected move, got ")"`)
+
+	_, err = ParseGames(strings.NewReader("(1. e4) *"))
+	assert.EqualError(err, "game 1, line 1, column 1: there is no move before the variation")
+
+	_, err = ParseGames(strings.NewReader("1. e4 (1. d4 1-0) *"))
+	assert.EqualError(err, "game 1, line 1, column 14: result 1-0 inside a variation")
+
+	_, err = ParseGames(strings.NewReader("[Event \"unclosed]\n1. e4 *\n\n[Event \"next\"]\n1. d4 *"))
+	assert.EqualError(err, `game 1, line 1, column 8: expected tag value, got invalid character "\""`)
+
+	_, err = ParseGames(strings.NewReader("1. e4 $256 *"))
+	assert.EqualError(err, "game 1, line 1, column 7: NAG $256 is not valid")
+
+	pgns, err := ParseGames(strings.NewReader(""))
+	assert.Nil(err)
+	assert.Empty(pgns)
+}
+
+func TestParseGamesMatchesParseStringGames(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Rated Blitz game"]
+[Variant "Chess960"]
+[FEN "nrkbqrbn/pppppppp/8/8/8/8/PPPPPPPP/NRKBQRBN w KQkq - 0 1"]
+[Result "1-0"]
+
+1. e4 e5 2. Ng3 Ng6 3. Nb3 Nb6 1-0`
+
+	// Act
+	expected, errExpected := ParseStringGames(games)
+	pgns, err := ParseGames(strings.NewReader(games))
+
+	// Assert
+	assert.Nil(errExpected)
+	assert.Nil(err)
+	assert.NotNil(pgns[0].Tree)
+	assert.Equal([]string{"e2e4", "e7e5", "h1g3", "h8g6", "a1b3", "a8b6"}, pgns[0].UCIFormatMoves)
+	assert.Equal(expected, pgns)
+}
+
+func TestParseGamesMoveTree(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Annotated game"]
+
+{Opening lesson} 1. e4 $1 {best by test} e5 2. Nf3 (2. f4 {gambit} exf4 ({or} 2... d5!) 3. Nf3) 2... Nc6?! *`
+
+	// Act
+	pgns, err := ParseGames(strings.NewReader(games))
+
+	// Assert
+	assert.Nil(err)
+	root := pgns[0].Tree
+	assert.Equal("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", root.FEN)
+	assert.Equal([]string{"Opening lesson"}, root.Comments)
+
+	mainLine := root.MainLine()
+	assert.Len(mainLine, 4)
+	e4, e5, nf3, nc6 := mainLine[0], mainLine[1], mainLine[2], mainLine[3]
+	assert.Equal(&Node{SAN: "e4", UCI: "e2e4", FEN: "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		Comments: []string{"best by test"}, NAGs: []int{1}, Children: []*Node{e5}}, e4)
+	assert.Equal([]int{6}, nc6.NAGs)
+	assert.Nil(nf3.Variations())
+
+	variations := e5.Variations()
+	assert.Len(variations, 1)
+	f4 := variations[0]
+	assert.Equal("f2f4", f4.UCI)
+	assert.Equal([]string{"gambit"}, f4.Comments)
+
+	exf4 := f4.Children[0]
+	assert.Equal("exf4", exf4.SAN)
+	assert.Equal("Nf3", exf4.Children[0].SAN)
+
+	d5 := f4.Variations()
+	assert.Len(d5, 1)
+	assert.Equal("d5", d5[0].SAN)
+	assert.Equal([]string{"or"}, d5[0].StartingComments)
+	assert.Equal([]int{1}, d5[0].NAGs)
+}
+
+func TestParseGamesMoveTreeJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	// Act
+	pgns, err := ParseGames(strings.NewReader("1. e4 (1. d4 {queen pawn}) e5 *"))
+	encoded, errEncode := json.Marshal(pgns[0].Tree)
+
+	// Assert
+	assert.Nil(err)
+	assert.Nil(errEncode)
+
+	decoded := &Node{}
+	assert.Nil(json.Unmarshal(encoded, decoded))
+	assert.Equal(pgns[0].Tree, decoded)
+	assert.Contains(string(encoded), `"san":"d4","uci":"d2d4"`)
+}
@@ -1,7 +1,6 @@
 package pgn
 
 import (
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,12 +27,9 @@ func TestParseEachLineOfString(t *testing.T) {
 	
 	1. e4 c5 2. Nf3 a6 3. d4 cxd4 4. Nxd4 e5 5. Nb3 Nf6 6. Nc3 Bb4 7. f3 d5 8. exd5 Nxd5 9. Bd2 Nxc3 10. Bxc3 Bxc3+ 11. bxc3 Qxd1+ 12. Rxd1 Nc6 13. Nc5 b6 14. Ne4 Be6 15. Nd6+ Ke7 16. Bc4 Rhd8 17. Bxe6 Kxe6 18. Nc4 Rxd1+ 19. Kxd1 b5 20. Ne3 f5 21. Ke2 Rd8 22. Rb1 f4 23. Nd1 Rd5 24. Nf2 Na5 25. a4 bxa4 26. Rb6+ Rd6 27. Rb4 Nc6 28. Rxa4 a5 29. Ne4 Rd5 30. c4 Rd7 31. Nc5+ Kd6 32. Nxd7 Kxd7 33. Kd3 Kd6 34. Ke4 Kc5 35. Kf5 Kd4 36. Ke6 Ke3 37. Kd6 Nd4 38. Kxe5 Nc6+ 39. Kd6 
 	Nd4 40. c5 Kf2 41. Rxd4 1-0`
-	pgn := PGN{}
-	lines := strings.Split(game, "\n")
-
-	for _, v := range lines {
-		pgn.parsePlainTextPGNLine(v)
-	}
+	pgns, err := ParseStringGames(game)
+	assert.Nil(err)
+	pgn := pgns[0]
 
 	assert.Equal("Rated Blitz game", pgn.Event)
 	assert.Equal("https://lichess.org/R2Mc2Oi3", pgn.Site)
@@ -88,7 +84,9 @@ func TestParseStringGames(t *testing.T) {
 	
 	1. d4 Nf6 2. c4 c5 3. d5 g6 4. Nc3 d6 5. e4 Bg7 6. f4 O-O 7. Nf3 a6 8. e5 Nfd7 9. e6 Nf6 10. exf7+ Rxf7 11. Ng5 Bg4 12. Nxf7 Kxf7 13. Qb3 Qd7 14. h3 Bf5 15. Be2 e6 16. g4 Be4 17. Nxe4 Nxe4 18. f5 exf5 19. gxf5 gxf5 20. Bh5+ Kg8 21. O-O Qe7 22. Bf3 Bd4+ 23. Kh2 Qe5+ 24. Kh1 Nf2+ 25. Kg2 Qg7+ 26. Kh2 Be5+ 0-1`
 
-	pgns := ParseStringGames(games)
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
 
 	assert.Len(pgns, 2)
 	assert.Equal("Rated Blitz game", pgns[0].Event)
@@ -125,3 +123,200 @@ func TestParseStringGames(t *testing.T) {
 		"g6f5", "e2h5", "f7g8", "e1g1", "d7e7", "h5f3", "g7d4", "g1h2", "e7e5", "h2h1", "e4f2", "h1g2", "e5g7",
 		"g2h2", "d4e5"}, pgns[1].UCIFormatMoves)
 }
+
+func BenchmarkParseStringGames(b *testing.B) {
+	games := `[Event "Rated Blitz game"]
+[Site "https://lichess.org/4wybg79d"]
+[White "kakaobohne"]
+[Black "EddyRob"]
+[Result "0-1"]
+
+1. d4 Nf6 2. c4 c5 3. d5 g6 4. Nc3 d6 5. e4 Bg7 6. f4 O-O 7. Nf3 a6 8. e5 Nfd7 9. e6 Nf6 10. exf7+ Rxf7 11. Ng5 Bg4 12. Nxf7 Kxf7 13. Qb3 Qd7 14. h3 Bf5 15. Be2 e6 16. g4 Be4 17. Nxe4 Nxe4 18. f5 exf5 19. gxf5 gxf5 20. Bh5+ Kg8 21. O-O Qe7 22. Bf3 Bd4+ 23. Kh2 Qe5+ 24. Kh1 Nf2+ 25. Kg2 Qg7+ 26. Kh2 Be5+ 0-1`
+
+	for i := 0; i < b.N; i++ {
+		ParseStringGames(games)
+	}
+}
+
+func TestParseStringGamesWithPromotion(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Promotion"]
+[Result "1-0"]
+
+1. e4 d5 2. exd5 c6 3. dxc6 Nf6 4. cxb7 Nbd7 5. bxa8=Q 1-0`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
+	assert.Equal([]string{"e2e4", "d7d5", "e4d5", "c7c6", "d5c6", "g8f6", "c6b7", "b8d7", "b7a8Q"},
+		pgns[0].UCIFormatMoves)
+}
+
+func TestParseStringGamesWithIllegalMove(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Illegal"]
+[Result "*"]
+
+1. e4 e5 2. Ke3 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Empty(pgns)
+	assert.EqualError(err, "game 1, line 4, column 13: error calling board.ParseSAN: Ke3 is not a legal move")
+}
+
+func TestParseStringGamesChess960(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Rated Chess960 game"]
+[Site "https://lichess.org/abcdefgh"]
+[Result "*"]
+[Variant "Chess960"]
+[FEN "rk2r3/pppppppp/8/8/8/8/PPPPPPPP/RK2R3 w KQkq - 0 1"]
+[SetUp "1"]
+
+1. O-O-O O-O 2. e4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
+	assert.Equal("Chess960", pgns[0].Variant)
+	assert.Equal("rk2r3/pppppppp/8/8/8/8/PPPPPPPP/RK2R3 w KQkq - 0 1", pgns[0].FEN)
+	assert.Equal([]string{"b1a1", "b8e8", "e2e4"}, pgns[0].UCIFormatMoves)
+}
+
+func TestParseStringGamesFromFEN(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "From position"]
+[Result "*"]
+[FEN "4k3/8/8/8/8/8/4P3/R3K3 w Q - 0 1"]
+[SetUp "1"]
+
+1. O-O-O Ke7 2. e4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
+	assert.Equal([]string{"e1c1", "e8e7", "e2e4"}, pgns[0].UCIFormatMoves)
+}
+
+func TestParseStringGamesWithInvalidFEN(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "From position"]
+[Result "*"]
+[FEN "8/8/8/8/8/8/8/8 w - - 0 1"]
+
+1. e4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Empty(pgns)
+	assert.EqualError(err, "game 1, line 5, column 1: error calling board.TranslateFEN: "+
+		"invalid FEN piece_placement: there must be one white king, got 0")
+}
+
+func TestParseStringGamesVariants(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Rated Three-check game"]
+[Result "1-0"]
+[Variant "Three-check"]
+
+1. e4 e5 2. Bc4 Nc6 3. Bxf7+ Kxf7 4. Qh5+ g6 5. Qxg6+ 1-0
+
+
+[Event "Rated King of the Hill game"]
+[Result "0-1"]
+[Variant "King of the Hill"]
+
+1. d4 e5 2. dxe5 Ke7 3. Kd2 Ke6 4. Kd3 Kxe5 0-1
+
+
+[Event "Rated Racing Kings game"]
+[Result "*"]
+[Variant "Racing Kings"]
+
+1. Kh3 Ka3 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
+	assert.Equal([]string{"e2e4", "e7e5", "f1c4", "b8c6", "c4f7", "e8f7", "d1h5", "g7g6", "h5g6"},
+		pgns[0].UCIFormatMoves)
+	assert.Equal([]string{"d2d4", "e7e5", "d4e5", "e8e7", "e1d2", "e7e6", "d2d3", "e6e5"}, pgns[1].UCIFormatMoves)
+	assert.Equal([]string{"h2h3", "a2a3"}, pgns[2].UCIFormatMoves)
+}
+
+func TestParseStringGamesCaptureVariants(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Rated Atomic game"]
+[Result "0-1"]
+[Variant "Atomic"]
+
+1. e4 d5 2. exd5 Qxd2# 0-1
+
+
+[Event "Rated Antichess game"]
+[Result "*"]
+[Variant "Antichess"]
+
+1. e3 b5 2. Bxb5 Bb7 3. Bxd7 Qxd7 *
+
+
+[Event "Rated Crazyhouse game"]
+[Result "*"]
+[Variant "Crazyhouse"]
+
+1. e4 d5 2. exd5 Qxd5 3. Nc3 Qa5 4. P@d5 @e4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Nil(err)
+	assert.Equal([]string{"e2e4", "d7d5", "e4d5", "d8d2"}, pgns[0].UCIFormatMoves)
+	assert.Equal([]string{"e2e3", "b7b5", "f1b5", "c8b7", "b5d7", "d8d7"}, pgns[1].UCIFormatMoves)
+	assert.Equal([]string{"e2e4", "d7d5", "e4d5", "d8d5", "b1c3", "d5a5", "P@d5", "P@e4"}, pgns[2].UCIFormatMoves)
+}
+
+func TestParseStringGamesUnsupportedVariant(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Rated Horde game"]
+[Result "*"]
+[Variant "Horde"]
+
+1. e4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Empty(pgns)
+	assert.EqualError(err, `game 1, line 5, column 1: variant "Horde" is not supported`)
+}
+
+func TestParseStringGamesKeepsValidGames(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Valid"]
+[Result "1-0"]
+
+1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0
+
+[Event "Illegal"]
+[Result "*"]
+
+1. e4 e5 2. Ke3 *
+
+[Event "Also valid"]
+[Result "*"]
+
+1. d4 { [%clk 0:03:00] } 1... d5
+2. c4 *`
+
+	pgns, err := ParseStringGames(games)
+
+	assert.Len(pgns, 2)
+	assert.Equal("Valid", pgns[0].Event)
+	assert.Equal("Also valid", pgns[1].Event)
+	assert.Equal([]string{"d2d4", "d7d5", "c2c4"}, pgns[1].UCIFormatMoves)
+
+	var parseErrors ParseErrors
+	assert.ErrorAs(err, &parseErrors)
+	assert.Len(parseErrors, 1)
+	assert.Equal(2, parseErrors[0].Game)
+	assert.EqualError(err, "game 2, line 9, column 13: error calling board.ParseSAN: Ke3 is not a legal move")
+}
@@ -0,0 +1,35 @@
+package pgn
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reader reads games of a PGN one at a time, so only the game being read is kept in memory
+type Reader struct {
+	p *parser
+
+	// error reading input, returned by every Read after it happens
+	err error
+}
+
+// NewReader returns a reader of the games of r, which is read as games are needed
+func NewReader(r io.Reader) *Reader {
+	return &Reader{p: newParser(r)}
+}
+
+// Read returns the next game, or io.EOF if there are no more games.
+// a game that is not valid returns a *ParseError, and the next Read goes on with the following game.
+func (r *Reader) Read() (PGN, error) {
+	if r.err != nil {
+		return PGN{}, r.err
+	}
+
+	game, err := r.p.parseGame()
+	if r.p.lex.err != nil {
+		r.err = fmt.Errorf("error calling ReadRune: %w", r.p.lex.err)
+		return PGN{}, r.err
+	}
+
+	return game, err
+}
@@ -0,0 +1,77 @@
+package pgn
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReader struct {
+	r io.Reader
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+
+	return n, err
+}
+
+func TestReaderReadsOneGameAtATime(t *testing.T) {
+	assert := assert.New(t)
+	reader := NewReader(strings.NewReader(`[Event "first"]
+
+1. e4 e5 1-0
+
+[Event "illegal"]
+
+1. e5 *
+
+[Event "last"]
+
+1. d4 0-1`))
+
+	// Act & Assert
+	game, err := reader.Read()
+	assert.Nil(err)
+	assert.Equal("first", game.Event)
+	assert.Equal([]string{"e2e4", "e7e5"}, game.UCIFormatMoves)
+
+	_, err = reader.Read()
+	var parseErr *ParseError
+	assert.True(errors.As(err, &parseErr))
+	assert.Equal(2, parseErr.Game)
+	assert.Equal(7, parseErr.Line)
+
+	game, err = reader.Read()
+	assert.Nil(err)
+	assert.Equal("last", game.Event)
+	assert.Equal("0-1", game.Result)
+
+	_, err = reader.Read()
+	assert.Equal(io.EOF, err)
+	_, err = reader.Read()
+	assert.Equal(io.EOF, err)
+}
+
+func TestReaderInputError(t *testing.T) {
+	assert := assert.New(t)
+	reader := NewReader(failingReader{strings.NewReader("[Event \"cut\"]\n\n1. e4 e5")})
+
+	// Act
+	_, err := reader.Read()
+	_, errAgain := reader.Read()
+
+	// Assert
+	assert.EqualError(err, "error calling ReadRune: connection reset")
+	assert.Equal(err, errAgain)
+
+	games, err := ParseGames(failingReader{strings.NewReader("1. e4 *")})
+	assert.Empty(games)
+	assert.EqualError(err, "error calling ReadRune: connection reset")
+}
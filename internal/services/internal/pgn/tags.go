@@ -0,0 +1,108 @@
+package pgn
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Tag is a tag pair of a game like [WhiteElo "2048"]
+	Tag struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	// Tags are the tag pairs of a game in the order they are written, names are unique
+	Tags []Tag
+)
+
+const (
+	pgnDateLayout = "2006.01.02"
+	pgnTimeLayout = "15:04:05"
+)
+
+// Get returns the value of tag name and true, or false if there is not such tag
+func (t Tags) Get(name string) (string, bool) {
+	for _, tag := range t {
+		if tag.Name == name {
+			return tag.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Set replaces the value of tag name keeping its place, or adds it at the end if there is not such tag
+func (t *Tags) Set(name, value string) {
+	for i := range *t {
+		if (*t)[i].Name == name {
+			(*t)[i].Value = value
+			return
+		}
+	}
+
+	*t = append(*t, Tag{Name: name, Value: value})
+}
+
+// Tag returns the value of tag name, empty if game does not have it
+func (pgn PGN) Tag(name string) string {
+	value, _ := pgn.Tags.Get(name)
+	return value
+}
+
+// WhiteElo returns the rating of white player, false if it is unknown
+func (pgn PGN) WhiteElo() (int, bool) {
+	return pgn.intTag("WhiteElo")
+}
+
+// BlackElo returns the rating of black player, false if it is unknown
+func (pgn PGN) BlackElo() (int, bool) {
+	return pgn.intTag("BlackElo")
+}
+
+// WhiteRatingDiff returns the rating points white player won or lost with the game, false if it is unknown
+func (pgn PGN) WhiteRatingDiff() (int, bool) {
+	return pgn.intTag("WhiteRatingDiff")
+}
+
+// BlackRatingDiff returns the rating points black player won or lost with the game, false if it is unknown
+func (pgn PGN) BlackRatingDiff() (int, bool) {
+	return pgn.intTag("BlackRatingDiff")
+}
+
+// PlayedAt returns when game started from its UTCDate and UTCTime tags, or from its Date tag
+// at midnight UTC if it does not have them. returns false if the date is unknown,
+// even partially like "2024.??.??".
+func (pgn PGN) PlayedAt() (time.Time, bool) {
+	if date, ok := pgn.Tags.Get("UTCDate"); ok {
+		value, layout := date, pgnDateLayout
+		if clock, ok := pgn.Tags.Get("UTCTime"); ok {
+			value, layout = date+" "+clock, pgnDateLayout+" "+pgnTimeLayout
+		}
+
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	t, err := time.Parse(pgnDateLayout, pgn.Date)
+	return t, err == nil
+}
+
+// hasSetUp returns false if SetUp tag says game starts from the standard position, FEN tag is ignored then
+func (pgn PGN) hasSetUp() bool {
+	setUp, ok := pgn.Tags.Get("SetUp")
+	return !ok || setUp != "0"
+}
+
+// ratings can have a sign like "+5", and unknown ones are written "?" or "-"
+func (pgn PGN) intTag(name string) (int, bool) {
+	value, ok := pgn.Tags.Get(name)
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	return n, err == nil
+}
@@ -0,0 +1,156 @@
+package pgn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const taggedGame = `[Event "Rated Blitz game"]
+[Site "https://lichess.org/R2Mc2Oi3"]
+[Date "2024.02.09"]
+[White "EddyRob"]
+[Black "Steevie"]
+[Result "1-0"]
+[UTCDate "2024.02.09"]
+[UTCTime "19:51:46"]
+[WhiteElo "2048"]
+[BlackElo "?"]
+[WhiteRatingDiff "+5"]
+[BlackRatingDiff "-6"]
+[Opening "Sicilian Defense: Old Sicilian"]
+[Annotator "coach"]
+[MyCustomTag "anything"]
+
+1. e4 c5 1-0`
+
+func TestParseGamesKeepsEveryTag(t *testing.T) {
+	assert := assert.New(t)
+
+	// Act
+	games, err := ParseGames(strings.NewReader(taggedGame))
+	plainGames, errPlain := ParseStringGames(taggedGame)
+
+	// Assert
+	assert.Nil(err)
+	assert.Nil(errPlain)
+	assert.Len(games[0].Tags, 15)
+	assert.Equal(games[0].Tags, plainGames[0].Tags)
+	assert.Equal(Tag{Name: "UTCTime", Value: "19:51:46"}, games[0].Tags[7])
+	assert.Equal(Tag{Name: "MyCustomTag", Value: "anything"}, games[0].Tags[14])
+
+	game := games[0]
+	assert.Equal("Sicilian Defense: Old Sicilian", game.Tag("Opening"))
+	assert.Equal("coach", game.Tag("Annotator"))
+	assert.Equal("", game.Tag("Termination"))
+
+	elo, ok := game.WhiteElo()
+	assert.True(ok)
+	assert.Equal(2048, elo)
+	_, ok = game.BlackElo()
+	assert.False(ok)
+
+	diff, ok := game.WhiteRatingDiff()
+	assert.True(ok)
+	assert.Equal(5, diff)
+	diff, ok = game.BlackRatingDiff()
+	assert.True(ok)
+	assert.Equal(-6, diff)
+
+	playedAt, ok := game.PlayedAt()
+	assert.True(ok)
+	assert.Equal(time.Date(2024, 2, 9, 19, 51, 46, 0, time.UTC), playedAt)
+}
+
+func TestPlayedAt(t *testing.T) {
+	assert := assert.New(t)
+
+	playedAt, ok := PGN{Date: "2023.12.31"}.PlayedAt()
+	assert.True(ok)
+	assert.Equal(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), playedAt)
+
+	playedAt, ok = PGN{Tags: Tags{{Name: "UTCDate", Value: "2024.01.02"}}}.PlayedAt()
+	assert.True(ok)
+	assert.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), playedAt)
+
+	_, ok = PGN{Date: "2024.??.??"}.PlayedAt()
+	assert.False(ok)
+}
+
+func TestTagsSet(t *testing.T) {
+	assert := assert.New(t)
+	tags := Tags{{Name: "Event", Value: "?"}, {Name: "Round", Value: "1"}}
+
+	// Act
+	tags.Set("Event", "Casual game")
+	tags.Set("Annotator", "coach")
+
+	// Assert
+	assert.Equal(Tags{{Name: "Event", Value: "Casual game"}, {Name: "Round", Value: "1"}, {Name: "Annotator", Value: "coach"}}, tags)
+	value, ok := tags.Get("Round")
+	assert.True(ok)
+	assert.Equal("1", value)
+	_, ok = tags.Get("Site")
+	assert.False(ok)
+}
+
+func TestSetUpTag(t *testing.T) {
+	assert := assert.New(t)
+	games := `[Event "Custom position"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. e4 Kd7 *
+
+[Event "SetUp 0 ignores FEN"]
+[SetUp "0"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. Nf3 *`
+
+	// Act
+	pgns, err := ParseGames(strings.NewReader(games))
+
+	// Assert
+	assert.Nil(err)
+	assert.Equal([]string{"e2e4", "e8d7"}, pgns[0].UCIFormatMoves)
+	assert.Equal("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", pgns[0].Tree.FEN)
+	assert.Equal([]string{"g1f3"}, pgns[1].UCIFormatMoves)
+}
+
+func TestWriteKeepsEveryTag(t *testing.T) {
+	assert := assert.New(t)
+	games, err := ParseGames(strings.NewReader(`[Black "bob"]
+[Round "3"]
+[Annotator "coach"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+[White "alice"]
+
+1. e4 *`))
+	assert.Nil(err)
+	games[0].Black = "carol"
+
+	// Act
+	buf := &bytes.Buffer{}
+	err = Write(buf, games...)
+
+	// Assert
+	assert.Nil(err)
+	assert.Equal(`[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "3"]
+[White "alice"]
+[Black "carol"]
+[Result "*"]
+[Annotator "coach"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. e4 *
+
+`, buf.String())
+}
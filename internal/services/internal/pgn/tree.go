@@ -0,0 +1,42 @@
+package pgn
+
+// Node is a position of a game, reached by playing Move from the position of its parent.
+// root node of a game is its starting position, so it has no move.
+type Node struct {
+	SAN string `json:"san,omitempty"`
+	UCI string `json:"uci,omitempty"`
+
+	// position after the move
+	FEN string `json:"fen"`
+
+	// comments written before the move, only the first move of a variation can have them
+	StartingComments []string `json:"starting_comments,omitempty"`
+
+	// comments written after the move, or before the first move for the root
+	Comments []string `json:"comments,omitempty"`
+
+	// numeric annotation glyphs of the move like 1 for "!" or 4 for "??"
+	NAGs []int `json:"nags,omitempty"`
+
+	// moves played from this position, first one is the main line and the rest are variations
+	Children []*Node `json:"children,omitempty"`
+}
+
+// MainLine returns the nodes of the moves of the main line that follows n, n excluded
+func (n *Node) MainLine() []*Node {
+	var line []*Node
+	for node := n; len(node.Children) > 0; node = node.Children[0] {
+		line = append(line, node.Children[0])
+	}
+
+	return line
+}
+
+// Variations returns the moves played instead of n main line one, nil if there are not any
+func (n *Node) Variations() []*Node {
+	if len(n.Children) < 2 {
+		return nil
+	}
+
+	return n.Children[1:]
+}
@@ -0,0 +1,265 @@
+package pgn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// move text lines are not longer than it, unless a single token is
+const maxLineLength = 80
+
+// tags of the Seven Tag Roster with the values written when they are unknown, in export order
+var sevenTagRoster = []struct{ name, unknown string }{
+	{"Event", "?"},
+	{"Site", "?"},
+	{"Date", "????.??.??"},
+	{"Round", "?"},
+	{"White", "?"},
+	{"Black", "?"},
+	{"Result", resultUnknown},
+}
+
+// encoder builds the move text of a game as tokens, and wraps them in lines
+type encoder struct {
+	tokens []string
+
+	// written before the next token, like the "(" of a variation
+	prefix string
+}
+
+// Write writes games in PGN export format: Seven Tag Roster first and the rest of tags after it,
+// then move text with move numbers, SAN, comments, NAGs and variations, wrapped at 80 columns.
+// move tree of a game is written if it has one, otherwise its UCIFormatMoves are.
+func Write(w io.Writer, games ...PGN) error {
+	bw := bufio.NewWriter(w)
+	for _, game := range games {
+		if err := writeGame(bw, game); err != nil {
+			return err
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error calling Flush: %w", err)
+	}
+
+	return nil
+}
+
+func writeGame(w *bufio.Writer, game PGN) error {
+	tree := game.Tree
+	if tree == nil {
+		var err error
+		if tree, err = game.uciTree(); err != nil {
+			return err
+		}
+	}
+
+	ply, err := game.startingPly(tree)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range game.exportTags() {
+		fmt.Fprintf(w, "[%s \"%s\"]\n", tag.Name, escapeTagValue(tag.Value))
+	}
+	w.WriteString("\n")
+
+	result := game.Result
+	if result == "" {
+		result = resultUnknown
+	}
+
+	e := &encoder{}
+	e.writeComments(tree.Comments)
+	e.writeLine(tree, ply, true)
+	e.token(result)
+
+	for _, line := range e.lines() {
+		w.WriteString(line + "\n")
+	}
+	w.WriteString("\n")
+
+	return nil
+}
+
+// returns tags of game in export order, Seven Tag Roster first and the rest in their order.
+// field values are written instead of the ones of their tags, so changes to fields are kept.
+func (pgn PGN) exportTags() Tags {
+	values := map[string]string{
+		"Event": pgn.Event, "Site": pgn.Site, "Date": pgn.Date, "Round": pgn.Tag("Round"),
+		"White": pgn.White, "Black": pgn.Black, "Result": pgn.Result,
+	}
+
+	tags := Tags{}
+	for _, tag := range sevenTagRoster {
+		value := values[tag.name]
+		if value == "" {
+			value = tag.unknown
+		}
+		tags = append(tags, Tag{Name: tag.name, Value: value})
+	}
+
+	for _, tag := range pgn.Tags {
+		if _, ok := values[tag.Name]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, tag := range []Tag{{"Variant", pgn.Variant}, {"TimeControl", pgn.TimeControl}, {"ECO", pgn.ECO}, {"FEN", pgn.FEN}} {
+		if tag.Value != "" {
+			tags.Set(tag.Name, tag.Value)
+		}
+	}
+
+	// SetUp tells a FEN is the starting position, it goes right before it
+	if _, ok := tags.Get("SetUp"); pgn.FEN != "" && !ok {
+		for i, tag := range tags {
+			if tag.Name == "FEN" {
+				tags = append(tags[:i], append(Tags{{Name: "SetUp", Value: "1"}}, tags[i:]...)...)
+				break
+			}
+		}
+	}
+
+	return tags
+}
+
+// returns a tree with the main line of UCIFormatMoves
+func (pgn PGN) uciTree() (*Node, error) {
+	board, err := pgn.StartingBoard()
+	if err != nil {
+		return nil, err
+	}
+
+	root := &Node{FEN: board.FEN()}
+	node := root
+	for _, move := range pgn.UCIFormatMoves {
+		m, err := board.ParseMove(move)
+		if err != nil {
+			return nil, fmt.Errorf("error calling board.ParseMove: %w", err)
+		}
+
+		child := &Node{SAN: board.SAN(m)}
+		board.Play(m)
+		child.UCI, child.FEN = move, board.FEN()
+
+		node.Children = append(node.Children, child)
+		node = child
+	}
+
+	return root, nil
+}
+
+// writes the moves that follow parent, with the variations of each one after it.
+// ply is the one of parent children, counted from 0 for the first white move.
+// forceNumber writes the move number of a first black move, as after a comment.
+func (e *encoder) writeLine(parent *Node, ply int, forceNumber bool) {
+	for len(parent.Children) > 0 {
+		main := parent.Children[0]
+		e.writeMove(main, ply, forceNumber)
+		forceNumber = len(main.Comments) > 0
+
+		for _, variation := range parent.Variations() {
+			e.prefix = "("
+			e.writeMove(variation, ply, true)
+			e.writeLine(variation, ply+1, len(variation.Comments) > 0)
+			e.tokens[len(e.tokens)-1] += ")"
+			forceNumber = true
+		}
+
+		parent = main
+		ply++
+	}
+}
+
+func (e *encoder) writeMove(n *Node, ply int, forceNumber bool) {
+	e.writeComments(n.StartingComments)
+
+	number := strconv.Itoa(ply/2 + 1)
+	switch {
+	case ply%2 == 0:
+		e.token(number + ".")
+	case forceNumber || len(n.StartingComments) > 0:
+		e.token(number + "...")
+	}
+
+	e.token(n.SAN)
+	for _, nag := range n.NAGs {
+		e.token("$" + strconv.Itoa(nag))
+	}
+	e.writeComments(n.Comments)
+}
+
+// comments are split in words so lines can be wrapped inside them,
+// "}" can not be escaped in a comment so it is removed.
+func (e *encoder) writeComments(comments []string) {
+	for _, comment := range comments {
+		words := strings.Fields(strings.ReplaceAll(comment, "}", ""))
+		if len(words) == 0 {
+			e.token("{}")
+			continue
+		}
+
+		words[0] = "{" + words[0]
+		words[len(words)-1] += "}"
+		for _, word := range words {
+			e.token(word)
+		}
+	}
+}
+
+func (e *encoder) token(t string) {
+	e.tokens = append(e.tokens, e.prefix+t)
+	e.prefix = ""
+}
+
+// returns tokens joined by spaces in lines of at most maxLineLength
+func (e *encoder) lines() []string {
+	var lines []string
+	line := ""
+	for _, t := range e.tokens {
+		switch {
+		case line == "":
+			line = t
+		case len(line)+1+len(t) > maxLineLength:
+			lines = append(lines, line)
+			line = t
+		default:
+			line += " " + t
+		}
+	}
+
+	return append(lines, line)
+}
+
+// returns the ply the move tree starts at, from the counters of its first position
+// read with the rules of the game variant
+func (pgn PGN) startingPly(tree *Node) (int, error) {
+	board, err := pgn.StartingBoard()
+	if err != nil {
+		return 0, err
+	}
+
+	if tree.FEN != "" {
+		if err := board.TranslateFEN(tree.FEN); err != nil {
+			return 0, fmt.Errorf("error calling board.TranslateFEN: %w", err)
+		}
+	}
+
+	ply := (board.MovesCount - 1) * 2
+	if ply < 0 {
+		ply = 0
+	}
+	if board.Turn == "b" {
+		ply++
+	}
+
+	return ply, nil
+}
+
+func escapeTagValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
@@ -0,0 +1,128 @@
+package pgn
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteAnnotatedGame(t *testing.T) {
+	assert := assert.New(t)
+	games, err := ParseGames(strings.NewReader(`[White "Player \"The\" One"]
+[Event "Lesson"]
+[ECO "C20"]
+
+{Opening lesson} 1. e4 $1 {best by test} e5 2. Nf3 (2. f4 {gambit} exf4 ({or} 2... d5!) 3. Nf3) 2... Nc6?! 1-0`))
+	assert.Nil(err)
+
+	// Act
+	buf := &bytes.Buffer{}
+	err = Write(buf, games...)
+
+	// Assert
+	assert.Nil(err)
+	assert.Equal(`[Event "Lesson"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "Player \"The\" One"]
+[Black "?"]
+[Result "1-0"]
+[ECO "C20"]
+
+{Opening lesson} 1. e4 $1 {best by test} 1... e5 2. Nf3 (2. f4 {gambit} 2...
+exf4 ({or} 2... d5 $1) 3. Nf3) 2... Nc6 $6 1-0
+
+`, buf.String())
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	games, err := ParseGames(strings.NewReader(`[Event "First"]
+[Result "0-1"]
+
+1. e4 e5 (1... c5 2. Nf3 (2. c3) 2... d6) 2. Nf3 {a very long comment that has to be wrapped in more than one line since it does not fit} Nc6 0-1
+
+[Event "From position"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 40"]
+
+40... Kd7 41. e4 {}  *`))
+	assert.Nil(err)
+
+	// Act
+	buf := &bytes.Buffer{}
+	err = Write(buf, games...)
+	written, errParse := ParseGames(bytes.NewReader(buf.Bytes()))
+
+	// Assert
+	assert.Nil(err)
+	assert.Nil(errParse)
+	assert.Len(written, 2)
+	for i := range games {
+		assert.Equal(games[i].Tree, written[i].Tree)
+		assert.Equal(games[i].UCIFormatMoves, written[i].UCIFormatMoves)
+		assert.Equal(games[i].Result, written[i].Result)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		assert.LessOrEqual(len(line), 80)
+	}
+	assert.Contains(buf.String(), "[SetUp \"1\"]\n[FEN \"4k3/8/8/8/8/8/4P3/4K3 b - - 0 40\"]\n\n40... Kd7 41. e4 {} *\n")
+}
+
+func TestWriteUCIFormatMoves(t *testing.T) {
+	assert := assert.New(t)
+	games, err := ParseStringGames(`[Event "Plain"]
+[Variant "Crazyhouse"]
+[Result "1-0"]
+
+1. e4 d5 2. exd5 Qxd5 3. Nc3 Qa5 4. P@d5 1-0`)
+	assert.Nil(err)
+	games[0].Tree = nil
+
+	// Act
+	buf := &bytes.Buffer{}
+	err = Write(buf, games...)
+
+	// Assert
+	assert.Nil(err)
+	assert.True(strings.HasSuffix(buf.String(), "[Variant \"Crazyhouse\"]\n\n1. e4 d5 2. exd5 Qxd5 3. Nc3 Qa5 4. P@d5 1-0\n\n"))
+}
+
+func TestWriteThreeCheckFENMoveNumbers(t *testing.T) {
+	assert := assert.New(t)
+	games, err := ParseStringGames(`[Event "Three-check"]
+[Variant "Three-check"]
+[FEN "4k3/8/8/8/8/8/8/R3K3 b - - 2+3 4 25"]
+[Result "*"]
+
+25... Kd7 26. Ra7+ *`)
+	assert.Nil(err)
+
+	// Act
+	buf := &bytes.Buffer{}
+	err = Write(buf, games...)
+
+	// Assert
+	assert.Nil(err)
+	assert.Contains(buf.String(), "\n\n25... Kd7 26. Ra7+ *\n")
+}
+
+func TestWriteErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	err := Write(&bytes.Buffer{}, PGN{UCIFormatMoves: []string{"e2e5"}})
+	assert.EqualError(err, "error calling board.ParseMove: e2e5 is not a legal move")
+
+	err = Write(failingWriter{}, PGN{})
+	assert.EqualError(err, "error calling Flush: disk full")
+}
@@ -1,7 +1,12 @@
 package internal
 
+import (
+	"context"
+	"io"
+)
+
 type (
 	ChessPlatform interface {
-		GetGamesFromManyDays(user string, daysAgo int) (string, error)
+		GetGamesFromManyDays(ctx context.Context, user string, daysAgo int) (io.ReadCloser, error)
 	}
 )
@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -11,7 +13,7 @@ type (
 	lichessPlatform struct{}
 )
 
-const url string = "https://lichess.org/api/games/user/%s?since=%d&perfType=blitz,rapid,classical"
+const gamesURL string = "https://lichess.org/api/games/user/%s?since=%d&perfType=blitz,rapid,classical"
 
 func NewLichessPlatform() ChessPlatform {
 	return lichessPlatform{}
@@ -20,51 +22,35 @@ func NewLichessPlatform() ChessPlatform {
 // GetGamesFromManyDays
 // user: user name from Lichess
 // daysAgo: how many days ago games you want
-// This function make a POST request to Lichess API looking for user games.
-// After request the function return user games as string.
-// Function return error if request or read response failed.
-func (lichessPlatform) GetGamesFromManyDays(user string, daysAgo int) (string, error) {
-	response, err := makeLichessRequest(user, daysAgo)
+// This function make a GET request to Lichess API looking for user games.
+// After request the function return user games as a PGN stream, which caller must close.
+// Games are downloaded as they are read, so they are never kept in memory at once.
+// Request is canceled when ctx is done, stream reads included.
+// Function return error if request failed.
+func (lichessPlatform) GetGamesFromManyDays(ctx context.Context, user string, daysAgo int) (io.ReadCloser, error) {
+	response, err := makeLichessRequest(ctx, user, daysAgo)
 	if err != nil {
-		return "", fmt.Errorf("error calling makeLichessRequest: %s", err)
+		return nil, fmt.Errorf("error calling makeLichessRequest: %s", err)
 	}
 
-	_, games, err := readRequestResponse(response)
-	if err != nil {
-		return "", fmt.Errorf("error calling readRequestResponse: %s", err)
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("lichess responded with status %s", response.Status)
 	}
 
-	return string(games), nil
+	return response.Body, nil
 }
 
-func makeLichessRequest(user string, daysAgo int) (*http.Response, error) {
-	url := fmt.Sprintf(url, user, getTimeinTimestamp(daysAgo))
-	return http.Get(url)
+func makeLichessRequest(ctx context.Context, user string, daysAgo int) (*http.Response, error) {
+	requestURL := fmt.Sprintf(gamesURL, url.PathEscape(user), getTimeinTimestamp(daysAgo))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error calling http.NewRequestWithContext: %w", err)
+	}
+
+	return http.DefaultClient.Do(request)
 }
 
 func getTimeinTimestamp(daysAgo int) int64 {
 	return time.Now().AddDate(0, 0, -daysAgo).UnixMilli()
 }
-
-func readRequestResponse(resp *http.Response) (int, []byte, error) {
-	bytesRead := 0
-	buf := make([]byte, 1024*1024)
-	game := []byte{}
-
-	for {
-		n, err := resp.Body.Read(buf)
-		bytesRead += n
-
-		game = append(game, buf...)
-
-		if err == io.EOF {
-			break
-		}
-
-		if err != nil {
-			return 0, nil, err
-		}
-	}
-
-	return bytesRead, game, nil
-}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"strings"
+
+	"chenizz/internal/services/internal/chess"
+)
+
+type (
+	// Evaluator scores a position in centipawns from the point of view of its side to move
+	Evaluator interface {
+		Evaluate(board *chess.Board) int
+	}
+
+	// Material is an Evaluator that only counts pieces
+	Material struct{}
+)
+
+var materialValues = map[chess.Piece]int{
+	chess.WPawn: 100, chess.WKnight: 300, chess.WBishop: 300, chess.WRook: 500, chess.WQueen: 900,
+}
+
+func (Material) Evaluate(board *chess.Board) int {
+	score := 0
+	for white, value := range materialValues {
+		black := chess.Piece(strings.ToLower(string(white)))
+		score += value * (board.Count(white) - board.Count(black))
+	}
+
+	if board.Turn == "b" {
+		return -score
+	}
+
+	return score
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"sort"
+
+	"chenizz/internal/services/internal/chess"
+)
+
+// piece values in centipawns used to order captures, most valuable victim first
+// and least valuable attacker first between captures of the same victim
+var orderValues = map[chess.Piece]int{
+	chess.WPawn: 100, chess.WKnight: 300, chess.WBishop: 300, chess.WRook: 500, chess.WQueen: 900, chess.WKing: 1000,
+	chess.BPawn: 100, chess.BKnight: 300, chess.BBishop: 300, chess.BRook: 500, chess.BQueen: 900, chess.BKing: 1000,
+}
+
+// moves sorted by their order scores, highest first
+type byOrder struct {
+	moves  []chess.Move
+	scores []int
+}
+
+const (
+	ttMoveOrder    = 1000000
+	captureOrder   = 100000
+	promotionOrder = 90000
+	killerOrder    = 80000
+)
+
+// sorts moves so the ones most likely to be the best are searched first:
+// transposition table move, captures, promotions and killer moves
+func (s *searcher) orderMoves(moves []chess.Move, ttMove chess.Move, ply int) {
+	scores := make([]int, len(moves))
+	for i, m := range moves {
+		scores[i] = s.orderScore(m, ttMove, ply)
+	}
+
+	sort.Stable(byOrder{moves: moves, scores: scores})
+}
+
+func (o byOrder) Len() int {
+	return len(o.moves)
+}
+
+func (o byOrder) Less(i, j int) bool {
+	return o.scores[i] > o.scores[j]
+}
+
+func (o byOrder) Swap(i, j int) {
+	o.moves[i], o.moves[j] = o.moves[j], o.moves[i]
+	o.scores[i], o.scores[j] = o.scores[j], o.scores[i]
+}
+
+func (s *searcher) orderScore(m chess.Move, ttMove chess.Move, ply int) int {
+	switch {
+	case m == ttMove:
+		return ttMoveOrder
+	case m.IsCapture():
+		return captureOrder + orderValues[m.Captured()]*10 - orderValues[m.Piece()]
+	case m.Promotion() != "":
+		return promotionOrder + orderValues[m.Promotion()]
+	case m == s.killers[ply][0]:
+		return killerOrder + 1
+	case m == s.killers[ply][1]:
+		return killerOrder
+	}
+
+	return 0
+}
+
+// quiet move that caused a beta cutoff, it is tried early in other positions of the same ply
+func (s *searcher) addKiller(ply int, m chess.Move) {
+	if s.killers[ply][0] == m {
+		return
+	}
+
+	s.killers[ply][1] = s.killers[ply][0]
+	s.killers[ply][0] = m
+}
+
+// captures and promotions that do not lose material, the only moves searched in quiescence
+func (s *searcher) goodCaptures(moves []chess.Move) []chess.Move {
+	good := moves[:0]
+	for _, m := range moves {
+		if (m.IsCapture() || m.Promotion() != "") && s.board.SEE(m) >= 0 {
+			good = append(good, m)
+		}
+	}
+
+	return good
+}
@@ -0,0 +1,417 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chenizz/internal/services/internal/chess"
+)
+
+type (
+	// Limits tells when a search must stop, zero values mean no limit.
+	// a search without limits runs until its context is done.
+	Limits struct {
+		// depth in plies of the last iteration
+		Depth int
+
+		// nodes visited, quiescence ones included
+		Nodes int
+
+		// time the search can take
+		MoveTime time.Duration
+	}
+
+	// Score of a position for its side to move
+	Score struct {
+		// material advantage in hundredths of a pawn, zero if score is a mate
+		Centipawns int
+
+		// true if there is a forced mate in Mate moves
+		IsMate bool
+
+		// moves until mate, negative if side to move gets mated. zero if it is already mated
+		Mate int
+	}
+
+	// Result of the last completed iteration of a search
+	Result struct {
+		BestMove chess.Move
+		Score    Score
+
+		// principal variation, moves both players are expected to play starting with BestMove
+		PV []chess.Move
+
+		Depth int
+		Nodes int
+		Time  time.Duration
+	}
+
+	// Engine searches the best move of a position with iterative deepening alpha-beta.
+	// it keeps its transposition table between searches, so it must not be used
+	// by more than one search at the same time.
+	Engine struct {
+		// called with the result of every completed iteration, it can be nil
+		OnIteration func(Result)
+
+		evaluator Evaluator
+		tt        *transpositionTable
+	}
+
+	// state of a single search
+	searcher struct {
+		ctx       context.Context
+		board     chess.Board
+		evaluator Evaluator
+		tt        *transpositionTable
+		limits    Limits
+		deadline  time.Time
+		start     time.Time
+		depth     int
+		nodes     int
+		stopped   bool
+
+		killers  [maxPly][2]chess.Move
+		pv       [maxPly + 1][maxPly + 1]chess.Move
+		pvLength [maxPly + 1]int
+	}
+)
+
+const (
+	maxPly = 64
+
+	infinity  = 1000000
+	mateScore = 100000
+
+	// scores above it are mates found in search
+	mateThreshold = mateScore - maxPly
+
+	// nodes searched between two checks of time and context
+	checkInterval = 2048
+
+	defaultHashMegabytes = 16
+)
+
+var noMove chess.Move
+
+// NewEngine returns an engine that scores positions with evaluator and uses a transposition
+// table of hashMegabytes. material count is used if evaluator is nil.
+func NewEngine(evaluator Evaluator, hashMegabytes int) *Engine {
+	if evaluator == nil {
+		evaluator = Material{}
+	}
+
+	if hashMegabytes <= 0 {
+		hashMegabytes = defaultHashMegabytes
+	}
+
+	return &Engine{evaluator: evaluator, tt: newTranspositionTable(hashMegabytes)}
+}
+
+// SetHashSize replaces transposition table with an empty one of hashMegabytes
+func (e *Engine) SetHashSize(hashMegabytes int) {
+	e.tt = newTranspositionTable(hashMegabytes)
+}
+
+// NewGame forgets positions searched before, it must be called when searches
+// are not from the same game anymore.
+func (e *Engine) NewGame() {
+	e.tt.clear()
+}
+
+// Search looks for the best move of board position until limits are reached or ctx is done.
+// first iteration is always completed, so result has a move unless the game is over.
+// board is not changed.
+func (e *Engine) Search(ctx context.Context, board chess.Board, limits Limits) Result {
+	s := &searcher{
+		ctx:       ctx,
+		board:     board.Clone(),
+		evaluator: e.evaluator,
+		tt:        e.tt,
+		limits:    limits,
+		start:     time.Now(),
+	}
+
+	if limits.MoveTime > 0 {
+		s.deadline = s.start.Add(limits.MoveTime)
+	}
+
+	maxDepth := maxPly - 1
+	if limits.Depth > 0 && limits.Depth < maxDepth {
+		maxDepth = limits.Depth
+	}
+
+	result := Result{}
+	if len(s.board.LegalMovesWithoutChecks()) == 0 {
+		result.Score = s.toScore(s.terminal(0))
+		return result
+	}
+
+	for s.depth = 1; s.depth <= maxDepth; s.depth++ {
+		score := s.negamax(s.depth, -infinity, infinity, 0)
+		if s.stopped {
+			break
+		}
+
+		result = Result{
+			BestMove: s.pv[0][0],
+			Score:    s.toScore(score),
+			PV:       append([]chess.Move(nil), s.pv[0][:s.pvLength[0]]...),
+			Depth:    s.depth,
+			Nodes:    s.nodes,
+			Time:     time.Since(s.start),
+		}
+
+		if e.OnIteration != nil {
+			e.OnIteration(result)
+		}
+
+		// a shorter mate can not be found searching deeper
+		if result.Score.IsMate && s.depth >= mateScore-abs(score) {
+			break
+		}
+	}
+
+	result.Nodes = s.nodes
+	result.Time = time.Since(s.start)
+	return result
+}
+
+// alpha-beta search of depth plies, ply is the distance from the root
+func (s *searcher) negamax(depth, alpha, beta, ply int) int {
+	s.pvLength[ply] = 0
+	if s.shouldStop() {
+		return 0
+	}
+
+	if ply > 0 && s.isDraw() {
+		return 0
+	}
+
+	inCheck := s.board.IsCheck
+	if inCheck && ply < maxPly/2 {
+		depth++
+	}
+
+	if depth <= 0 || ply >= maxPly-1 {
+		return s.quiescence(alpha, beta, ply)
+	}
+
+	s.nodes++
+	hash := s.board.Hash()
+	ttMove := noMove
+	if entry, ok := s.tt.probe(hash); ok {
+		ttMove = entry.move
+		if ply > 0 && int(entry.depth) >= depth {
+			score := scoreFromTT(int(entry.score), ply)
+			switch {
+			case entry.bound == exact,
+				entry.bound == lowerBound && score >= beta,
+				entry.bound == upperBound && score <= alpha:
+				return score
+			}
+		}
+	}
+
+	moves := s.board.LegalMovesWithoutChecks()
+	if len(moves) == 0 {
+		return s.terminal(ply)
+	}
+
+	s.orderMoves(moves, ttMove, ply)
+
+	originalAlpha := alpha
+	best, bestMove := -infinity, noMove
+	for _, m := range moves {
+		s.board.Play(m)
+		score := -s.negamax(depth-1, -beta, -alpha, ply+1)
+		s.board.UnmakeMove()
+
+		if s.stopped {
+			return 0
+		}
+
+		if score <= best {
+			continue
+		}
+
+		best, bestMove = score, m
+		if score <= alpha {
+			continue
+		}
+
+		alpha = score
+		s.updatePV(ply, m)
+		if alpha >= beta {
+			if !m.IsCapture() {
+				s.addKiller(ply, m)
+			}
+			break
+		}
+	}
+
+	b := exact
+	switch {
+	case best <= originalAlpha:
+		b = upperBound
+	case best >= beta:
+		b = lowerBound
+	}
+	s.tt.store(hash, depth, scoreToTT(best, ply), b, bestMove)
+
+	return best
+}
+
+// search of captures only until position is quiet, so it is not evaluated in the middle
+// of an exchange. side to move can stand pat unless it is in check.
+func (s *searcher) quiescence(alpha, beta, ply int) int {
+	s.pvLength[ply] = 0
+	if s.shouldStop() {
+		return 0
+	}
+
+	s.nodes++
+	moves := s.board.LegalMovesWithoutChecks()
+	if len(moves) == 0 {
+		return s.terminal(ply)
+	}
+
+	inCheck := s.board.IsCheck
+	if ply >= maxPly-1 {
+		return s.evaluator.Evaluate(&s.board)
+	}
+
+	if !inCheck {
+		standPat := s.evaluator.Evaluate(&s.board)
+		if standPat >= beta {
+			return standPat
+		}
+		if standPat > alpha {
+			alpha = standPat
+		}
+
+		moves = s.goodCaptures(moves)
+	}
+
+	s.orderMoves(moves, noMove, ply)
+
+	best := alpha
+	if inCheck {
+		best = -infinity
+	}
+
+	for _, m := range moves {
+		s.board.Play(m)
+		score := -s.quiescence(-beta, -alpha, ply+1)
+		s.board.UnmakeMove()
+
+		if s.stopped {
+			return 0
+		}
+
+		if score > best {
+			best = score
+		}
+
+		if score > alpha {
+			alpha = score
+			s.updatePV(ply, m)
+			if alpha >= beta {
+				break
+			}
+		}
+	}
+
+	return best
+}
+
+// score of a position without legal moves, or ended by variant rules
+func (s *searcher) terminal(ply int) int {
+	outcome := s.board.Outcome()
+
+	winner := ""
+	switch outcome.Result {
+	case "1-0":
+		winner = "w"
+	case "0-1":
+		winner = "b"
+	default:
+		return 0
+	}
+
+	if winner == s.board.Turn {
+		return mateScore - ply
+	}
+
+	return -mateScore + ply
+}
+
+// repetitions and fifty move rule are draws inside the search,
+// a position repeated once is scored as if the repetition went on.
+func (s *searcher) isDraw() bool {
+	return s.board.HalfMoves >= 100 || s.board.Repetitions() > 1 || s.board.IsInsufficientMaterial()
+}
+
+func (s *searcher) shouldStop() bool {
+	if s.stopped {
+		return true
+	}
+
+	// first iteration always ends, so there is a move to return
+	if s.depth <= 1 {
+		return false
+	}
+
+	if s.limits.Nodes > 0 && s.nodes >= s.limits.Nodes {
+		s.stopped = true
+		return true
+	}
+
+	if s.nodes%checkInterval != 0 {
+		return false
+	}
+
+	select {
+	case <-s.ctx.Done():
+		s.stopped = true
+	default:
+		s.stopped = !s.deadline.IsZero() && time.Now().After(s.deadline)
+	}
+
+	return s.stopped
+}
+
+// principal variation of ply is m followed by the one of next ply
+func (s *searcher) updatePV(ply int, m chess.Move) {
+	s.pv[ply][0] = m
+	copy(s.pv[ply][1:], s.pv[ply+1][:s.pvLength[ply+1]])
+	s.pvLength[ply] = s.pvLength[ply+1] + 1
+}
+
+func (s *searcher) toScore(score int) Score {
+	switch {
+	case score > mateThreshold:
+		return Score{IsMate: true, Mate: (mateScore - score + 1) / 2}
+	case score < -mateThreshold:
+		return Score{IsMate: true, Mate: -(mateScore + score + 1) / 2}
+	}
+
+	return Score{Centipawns: score}
+}
+
+// returns score as written in UCI info lines, like "cp 35" or "mate -2"
+func (s Score) String() string {
+	if s.IsMate {
+		return fmt.Sprintf("mate %d", s.Mate)
+	}
+
+	return fmt.Sprintf("cp %d", s.Centipawns)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,151 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chenizz/internal/services/internal/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func boardFromFEN(t *testing.T, fen string) chess.Board {
+	board := chess.Board{}
+	err := board.TranslateFEN(fen)
+	assert.Nil(t, err)
+	return board
+}
+
+func Test_Search_MateInOne(t *testing.T) {
+	board := boardFromFEN(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 4})
+
+	assert.Equal(t, "a1a8", result.BestMove.String())
+	assert.Equal(t, Score{IsMate: true, Mate: 1}, result.Score)
+	assert.Equal(t, "mate 1", result.Score.String())
+	assert.Equal(t, []string{"a1a8"}, moveStrings(result.PV))
+}
+
+func Test_Search_MateInTwo(t *testing.T) {
+	board := boardFromFEN(t, "r1b2k1r/ppp1bppp/8/1B1Q4/5q2/2P5/PPP2PPP/R3R1K1 w - - 1 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 5})
+
+	assert.Equal(t, "d5d8", result.BestMove.String())
+	assert.Equal(t, Score{IsMate: true, Mate: 2}, result.Score)
+	assert.Equal(t, []string{"d5d8", "e7d8", "e1e8"}, moveStrings(result.PV))
+}
+
+func Test_Search_GetsMated(t *testing.T) {
+	board := boardFromFEN(t, "R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 3})
+
+	assert.Equal(t, Score{IsMate: true, Mate: 0}, result.Score)
+	assert.Empty(t, result.PV)
+}
+
+func Test_Search_WinsHangingQueen(t *testing.T) {
+	board := boardFromFEN(t, "4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 3})
+
+	assert.Equal(t, "d2d5", result.BestMove.String())
+	assert.False(t, result.Score.IsMate)
+	assert.Greater(t, result.Score.Centipawns, 400)
+}
+
+func Test_Search_AvoidsLosingExchange(t *testing.T) {
+	board := boardFromFEN(t, "4k3/8/4p3/3p4/8/8/3Q4/4K3 w - - 0 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 2})
+
+	assert.NotEqual(t, "d2d5", result.BestMove.String())
+}
+
+func Test_Search_StalemateIsDraw(t *testing.T) {
+	board := boardFromFEN(t, "k7/8/1Q6/8/8/8/8/2K5 b - - 0 1")
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 3})
+
+	assert.Equal(t, Score{}, result.Score)
+	assert.Equal(t, 0, result.Depth)
+}
+
+func Test_Search_Limits(t *testing.T) {
+	board := chess.NewBoard()
+	engine := NewEngine(nil, 1)
+
+	iterations := 0
+	engine.OnIteration = func(r Result) {
+		iterations++
+		assert.Equal(t, iterations, r.Depth)
+	}
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 3})
+	assert.Equal(t, 3, result.Depth)
+	assert.Equal(t, 3, iterations)
+	assert.Len(t, board.MovesHistory, 0)
+
+	engine.OnIteration = nil
+	result = engine.Search(context.Background(), board, Limits{Nodes: 5000})
+	assert.LessOrEqual(t, result.Nodes, 5000+checkInterval)
+	assert.Greater(t, result.Depth, 0)
+
+	start := time.Now()
+	result = engine.Search(context.Background(), board, Limits{MoveTime: 100 * time.Millisecond})
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NotEqual(t, "", result.BestMove.From())
+}
+
+func Test_Search_StopsWhenContextIsDone(t *testing.T) {
+	board := chess.NewBoard()
+	engine := NewEngine(nil, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := engine.Search(ctx, board, Limits{})
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Greater(t, result.Depth, 0)
+	assert.Equal(t, result.BestMove, result.PV[0])
+}
+
+func Test_Search_Variant(t *testing.T) {
+	board := chess.Board{}
+	board.SetVariant(chess.KingOfTheHill)
+	err := board.TranslateFEN("4k3/8/8/8/8/3K4/8/8 w - - 0 1")
+	assert.Nil(t, err)
+	engine := NewEngine(nil, 1)
+
+	result := engine.Search(context.Background(), board, Limits{Depth: 2})
+
+	assert.Equal(t, Score{IsMate: true, Mate: 1}, result.Score)
+	assert.Contains(t, []string{"d3d4", "d3e4"}, result.BestMove.String())
+}
+
+func Test_Material(t *testing.T) {
+	board := boardFromFEN(t, "4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1")
+
+	assert.Equal(t, -400, Material{}.Evaluate(&board))
+
+	board.MakeMove("e1f1")
+	assert.Equal(t, 400, Material{}.Evaluate(&board))
+}
+
+func moveStrings(moves []chess.Move) []string {
+	strs := make([]string, 0, len(moves))
+	for _, m := range moves {
+		strs = append(strs, m.String())
+	}
+
+	return strs
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"unsafe"
+
+	"chenizz/internal/services/internal/chess"
+)
+
+type (
+	// tells if an entry score is the exact one or only a limit of it
+	bound uint8
+
+	ttEntry struct {
+		key   uint64
+		move  chess.Move
+		score int32
+		depth int8
+		bound bound
+	}
+
+	// transpositionTable keeps results of positions already searched, keyed by their Zobrist hash
+	transpositionTable struct {
+		entries []ttEntry
+		mask    uint64
+	}
+)
+
+const (
+	// entry is empty
+	noBound bound = iota
+	exact
+	lowerBound
+	upperBound
+)
+
+// returns a table of at most megabytes, number of entries is a power of two
+func newTranspositionTable(megabytes int) *transpositionTable {
+	size := uint64(1)
+	for (size*2)*uint64(unsafe.Sizeof(ttEntry{})) <= uint64(megabytes)<<20 {
+		size *= 2
+	}
+
+	return &transpositionTable{entries: make([]ttEntry, size), mask: size - 1}
+}
+
+func (tt *transpositionTable) probe(key uint64) (ttEntry, bool) {
+	entry := tt.entries[key&tt.mask]
+	return entry, entry.bound != noBound && entry.key == key
+}
+
+// saves the result of a search, replacing the entry of another position
+// or the one of the same position searched with less depth
+func (tt *transpositionTable) store(key uint64, depth, score int, b bound, move chess.Move) {
+	entry := &tt.entries[key&tt.mask]
+	if entry.key == key && int(entry.depth) > depth && b != exact {
+		return
+	}
+
+	*entry = ttEntry{key: key, move: move, score: int32(score), depth: int8(depth), bound: b}
+}
+
+func (tt *transpositionTable) clear() {
+	for i := range tt.entries {
+		tt.entries[i] = ttEntry{}
+	}
+}
+
+// mate scores are stored as distance from the position instead of from the root,
+// so they are right when the position is reached at another ply
+func scoreToTT(score, ply int) int {
+	switch {
+	case score > mateThreshold:
+		return score + ply
+	case score < -mateThreshold:
+		return score - ply
+	}
+
+	return score
+}
+
+func scoreFromTT(score, ply int) int {
+	switch {
+	case score > mateThreshold:
+		return score - ply
+	case score < -mateThreshold:
+		return score + ply
+	}
+
+	return score
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"testing"
+
+	"chenizz/internal/services/internal/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_transpositionTable(t *testing.T) {
+	tt := newTranspositionTable(1)
+	board := chess.NewBoard()
+	m, _ := board.ParseMove("e2e4")
+
+	_, ok := tt.probe(board.Hash())
+	assert.False(t, ok)
+
+	tt.store(board.Hash(), 5, 30, exact, m)
+	entry, ok := tt.probe(board.Hash())
+	assert.True(t, ok)
+	assert.Equal(t, m, entry.move)
+	assert.Equal(t, int32(30), entry.score)
+
+	// shallower bounds do not replace deeper results
+	tt.store(board.Hash(), 2, 10, lowerBound, noMove)
+	entry, _ = tt.probe(board.Hash())
+	assert.Equal(t, int8(5), entry.depth)
+
+	tt.clear()
+	_, ok = tt.probe(board.Hash())
+	assert.False(t, ok)
+}
+
+func Test_mateScoresInTT(t *testing.T) {
+	score := mateScore - 7
+
+	assert.Equal(t, mateScore-4, scoreToTT(score, 3))
+	assert.Equal(t, score, scoreFromTT(scoreToTT(score, 3), 3))
+	assert.Equal(t, -mateScore+4, scoreToTT(-score, 3))
+	assert.Equal(t, 25, scoreToTT(25, 3))
+}
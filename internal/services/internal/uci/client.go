@@ -0,0 +1,390 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chenizz/internal/services/internal/search"
+)
+
+type (
+	// Client drives an engine process that speaks UCI, like Stockfish.
+	// its methods can be called from several goroutines, they are run one at a time.
+	Client struct {
+		// engine name and author as sent by the engine
+		Name   string
+		Author string
+
+		// option lines sent by the engine, by option name
+		Options map[string]string
+
+		// lines an analysis has, as set with the MultiPV option
+		multiPV int
+
+		cmd    *exec.Cmd
+		stdin  io.WriteCloser
+		lines  chan string
+		exited chan struct{}
+		mu     sync.Mutex
+	}
+
+	// Analysis is the result of a search made by an engine
+	Analysis struct {
+		// best move and the one engine expects as answer in UCI notation,
+		// BestMove is "0000" or empty if there are no legal moves
+		BestMove string
+		Ponder   string
+
+		// last info received of every line, Lines[0] is the best one
+		Lines []Info
+	}
+)
+
+// ErrEngineExited is returned when the engine process ends while it is in use
+var ErrEngineExited = errors.New("engine process exited")
+
+const (
+	// time an engine has to answer isready or to stop a search
+	responseTimeout = 10 * time.Second
+
+	// time an engine has to exit after quit before it is killed
+	quitTimeout = time.Second
+)
+
+// Start runs the engine at path with args and makes the UCI handshake.
+// ctx only limits the handshake, use Close to end the engine.
+func Start(ctx context.Context, path string, args ...string) (*Client, error) {
+	cmd := exec.Command(path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error calling cmd.StdinPipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error calling cmd.StdoutPipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting engine %s: %w", path, err)
+	}
+
+	c := &Client{
+		Options: map[string]string{},
+		multiPV: 1,
+		cmd:     cmd,
+		stdin:   stdin,
+		lines:   make(chan string, 256),
+		exited:  make(chan struct{}),
+	}
+	go c.read(stdout)
+
+	if err := c.handshake(ctx); err != nil {
+		c.kill()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Client) handshake(ctx context.Context) error {
+	if err := c.send("uci"); err != nil {
+		return err
+	}
+
+	lines, err := c.waitFor(ctx, "uciok")
+	if err != nil {
+		return fmt.Errorf("error waiting for uciok: %w", err)
+	}
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "id name "):
+			c.Name = strings.TrimPrefix(line, "id name ")
+		case strings.HasPrefix(line, "id author "):
+			c.Author = strings.TrimPrefix(line, "id author ")
+		case strings.HasPrefix(line, "option name "):
+			option := strings.TrimPrefix(line, "option name ")
+			name := option
+			if i := strings.Index(option, " type "); i >= 0 {
+				name = option[:i]
+			}
+			c.Options[name] = option
+		}
+	}
+
+	return c.isReady(ctx)
+}
+
+// IsReady waits until the engine has processed every command sent before
+func (c *Client) IsReady(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.isReady(ctx)
+}
+
+// SetOption changes an engine option, like "Hash" or "MultiPV".
+// buttons, like "Clear Hash", are pushed with an empty value.
+func (c *Client) SetOption(ctx context.Context, name, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	command := "setoption name " + name
+	if value != "" {
+		command += " value " + value
+	}
+
+	if err := c.send(command); err != nil {
+		return err
+	}
+
+	if n, err := strconv.Atoi(value); err == nil && n > 0 && strings.EqualFold(name, "MultiPV") {
+		c.multiPV = n
+	}
+
+	return c.isReady(ctx)
+}
+
+// NewGame tells the engine next positions are from a different game
+func (c *Client) NewGame(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.send("ucinewgame"); err != nil {
+		return err
+	}
+
+	return c.isReady(ctx)
+}
+
+// Analyze searches the position reached playing moves from fen, or from the starting
+// position if fen is empty, until limits are reached. zero limits search until ctx is done.
+// onInfo is called with every info line with a score, it can be nil.
+// if ctx is done search is stopped and the analysis made so far is returned with ctx error.
+func (c *Client) Analyze(ctx context.Context, fen string, moves []string, limits search.Limits, onInfo func(Info)) (Analysis, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	position := "position startpos"
+	if fen != "" {
+		position = "position fen " + fen
+	}
+	if len(moves) > 0 {
+		position += " moves " + strings.Join(moves, " ")
+	}
+
+	if err := c.send(position); err != nil {
+		return Analysis{}, err
+	}
+
+	if err := c.send(goCommand(limits)); err != nil {
+		return Analysis{}, err
+	}
+
+	analysis := Analysis{}
+	maxLines := c.multiPV
+	for {
+		var line string
+		select {
+		case l, ok := <-c.lines:
+			if !ok {
+				return analysis, ErrEngineExited
+			}
+			line = l
+		case <-ctx.Done():
+			if err := c.send("stop"); err != nil {
+				return analysis, err
+			}
+
+			// the engine has some time to answer with its bestmove
+			stopCtx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+			err := c.readAnalysis(stopCtx, &analysis, maxLines, onInfo)
+			cancel()
+			if err != nil {
+				return analysis, err
+			}
+
+			return analysis, ctx.Err()
+		}
+
+		if done := analysis.add(line, maxLines, onInfo); done {
+			return analysis, nil
+		}
+	}
+}
+
+// reads lines until bestmove
+func (c *Client) readAnalysis(ctx context.Context, analysis *Analysis, maxLines int, onInfo func(Info)) error {
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				return ErrEngineExited
+			}
+
+			if analysis.add(line, maxLines, onInfo) {
+				return nil
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("engine did not send bestmove: %w", ctx.Err())
+		}
+	}
+}
+
+// adds an engine line to analysis, returns true if it is the bestmove one.
+// info lines of a multipv out of 1 to maxLines are ignored, since no such line was asked for.
+func (a *Analysis) add(line string, maxLines int, onInfo func(Info)) bool {
+	if strings.HasPrefix(line, "bestmove") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 {
+			a.BestMove = fields[1]
+		}
+		if len(fields) > 3 && fields[2] == "ponder" {
+			a.Ponder = fields[3]
+		}
+		return true
+	}
+
+	info, ok := parseInfo(line)
+	if !ok || info.MultiPV < 1 || info.MultiPV > maxLines {
+		return false
+	}
+
+	for len(a.Lines) < info.MultiPV {
+		a.Lines = append(a.Lines, Info{})
+	}
+	a.Lines[info.MultiPV-1] = info
+
+	if onInfo != nil {
+		onInfo(info)
+	}
+
+	return false
+}
+
+// Close asks the engine to quit and kills it if it does not exit in time
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.send("quit")
+	c.stdin.Close()
+	go c.drain()
+
+	select {
+	case <-c.exited:
+		return nil
+	case <-time.After(quitTimeout):
+		return c.kill()
+	}
+}
+
+// Exited returns a channel that is closed when the engine process ends
+func (c *Client) Exited() <-chan struct{} {
+	return c.exited
+}
+
+func (c *Client) kill() error {
+	go c.drain()
+	if err := c.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("error killing engine: %w", err)
+	}
+
+	<-c.exited
+	return nil
+}
+
+func (c *Client) isReady(ctx context.Context) error {
+	if err := c.send("isready"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, responseTimeout)
+	defer cancel()
+
+	if _, err := c.waitFor(ctx, "readyok"); err != nil {
+		return fmt.Errorf("error waiting for readyok: %w", err)
+	}
+
+	return nil
+}
+
+// returns lines sent by the engine until one equal to answer, that one excluded
+func (c *Client) waitFor(ctx context.Context, answer string) ([]string, error) {
+	lines := []string{}
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				return lines, ErrEngineExited
+			}
+
+			if line == answer {
+				return lines, nil
+			}
+
+			lines = append(lines, line)
+		case <-ctx.Done():
+			return lines, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) send(command string) error {
+	select {
+	case <-c.exited:
+		return ErrEngineExited
+	default:
+	}
+
+	if _, err := io.WriteString(c.stdin, command+"\n"); err != nil {
+		return fmt.Errorf("error sending %q to engine: %w", command, err)
+	}
+
+	return nil
+}
+
+// sends engine output to lines until the process ends
+func (c *Client) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		c.lines <- strings.TrimSpace(scanner.Text())
+	}
+
+	close(c.lines)
+	c.cmd.Wait()
+	close(c.exited)
+}
+
+// discards engine output, so it does not block writing while it exits
+func (c *Client) drain() {
+	for range c.lines {
+	}
+}
+
+func goCommand(limits search.Limits) string {
+	command := "go"
+	if limits.Depth > 0 {
+		command += " depth " + strconv.Itoa(limits.Depth)
+	}
+	if limits.Nodes > 0 {
+		command += " nodes " + strconv.Itoa(limits.Nodes)
+	}
+	if limits.MoveTime > 0 {
+		command += " movetime " + strconv.FormatInt(limits.MoveTime.Milliseconds(), 10)
+	}
+	if command == "go" {
+		command += " infinite"
+	}
+
+	return command
+}
@@ -0,0 +1,175 @@
+package uci
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"chenizz/internal/services/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func startFakeEngine(t *testing.T) *Client {
+	c, err := Start(context.Background(), os.Args[0])
+	assert.Nil(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func Test_Start(t *testing.T) {
+	c := startFakeEngine(t)
+
+	assert.Equal(t, "Fake Engine 1.0", c.Name)
+	assert.Equal(t, "Chenizz tests", c.Author)
+	assert.Equal(t, "MultiPV type spin default 1 min 1 max 5", c.Options["MultiPV"])
+	assert.Len(t, c.Options, 2)
+
+	assert.Nil(t, c.IsReady(context.Background()))
+	assert.Nil(t, c.NewGame(context.Background()))
+
+	_, err := Start(context.Background(), "./missing-engine")
+	assert.NotNil(t, err)
+}
+
+func Test_Analyze(t *testing.T) {
+	c := startFakeEngine(t)
+
+	infos := []Info{}
+	analysis, err := c.Analyze(context.Background(), "", []string{"d2d4"}, search.Limits{Depth: 3}, func(info Info) {
+		infos = append(infos, info)
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "e2e4", analysis.BestMove)
+	assert.Equal(t, "e7e5", analysis.Ponder)
+	assert.Len(t, infos, 3)
+	assert.Equal(t, []Info{{
+		Depth:    3,
+		SelDepth: 5,
+		MultiPV:  1,
+		Score:    search.Score{Centipawns: 20},
+		Nodes:    3000,
+		NPS:      100000,
+		Time:     30 * time.Millisecond,
+		PV:       []string{"e2e4", "e7e5"},
+	}}, analysis.Lines)
+}
+
+func Test_Analyze_MultiPV(t *testing.T) {
+	c := startFakeEngine(t)
+	assert.Nil(t, c.SetOption(context.Background(), "MultiPV", "3"))
+
+	analysis, err := c.Analyze(context.Background(), "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", nil, search.Limits{Depth: 2}, nil)
+
+	assert.Nil(t, err)
+	assert.Len(t, analysis.Lines, 3)
+	for i, line := range analysis.Lines {
+		assert.Equal(t, i+1, line.MultiPV)
+		assert.Equal(t, 2, line.Depth)
+		assert.Equal(t, 20-10*i, line.Score.Centipawns)
+	}
+}
+
+func Test_Analyze_InvalidMultiPV(t *testing.T) {
+	c := startFakeEngine(t)
+	assert.Nil(t, c.SetOption(context.Background(), "BadMultiPV", ""))
+
+	infos := 0
+	analysis, err := c.Analyze(context.Background(), "", nil, search.Limits{Depth: 2}, func(info Info) {
+		infos++
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, infos)
+	assert.Len(t, analysis.Lines, 1)
+	assert.Equal(t, []string{"e2e4", "e7e5"}, analysis.Lines[0].PV)
+}
+
+func Test_Analyze_Cancel(t *testing.T) {
+	c := startFakeEngine(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	analysis, err := c.Analyze(ctx, "", nil, search.Limits{}, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "e2e4", analysis.BestMove)
+	assert.Greater(t, analysis.Lines[0].Depth, 0)
+
+	// engine can be used again
+	analysis, err = c.Analyze(context.Background(), "", nil, search.Limits{Depth: 1}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, analysis.Lines[0].Depth)
+}
+
+func Test_Analyze_EngineExited(t *testing.T) {
+	c := startFakeEngine(t)
+	assert.Nil(t, c.SetOption(context.Background(), "Crash", ""))
+
+	_, err := c.Analyze(context.Background(), "", nil, search.Limits{Depth: 1}, nil)
+	assert.ErrorIs(t, err, ErrEngineExited)
+
+	select {
+	case <-c.Exited():
+	case <-time.After(time.Second):
+		t.Fatal("engine did not exit")
+	}
+
+	assert.ErrorIs(t, c.IsReady(context.Background()), ErrEngineExited)
+}
+
+func Test_Close(t *testing.T) {
+	c, err := Start(context.Background(), os.Args[0])
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.Close())
+	_, open := <-c.Exited()
+	assert.False(t, open)
+}
+
+func Test_parseInfo(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected Info
+		ok       bool
+	}{
+		{
+			line:     "info depth 12 seldepth 18 multipv 2 score cp -35 nodes 5000 nps 250000 hashfull 10 time 20 pv e7e5 g1f3",
+			expected: Info{Depth: 12, SelDepth: 18, MultiPV: 2, Score: search.Score{Centipawns: -35}, Nodes: 5000, NPS: 250000, Time: 20 * time.Millisecond, PV: []string{"e7e5", "g1f3"}},
+			ok:       true,
+		},
+		{
+			line:     "info depth 20 score mate -3 pv h7h8q",
+			expected: Info{Depth: 20, MultiPV: 1, Score: search.Score{IsMate: true, Mate: -3}, PV: []string{"h7h8q"}},
+			ok:       true,
+		},
+		{
+			line:     "info depth 8 score cp 15 lowerbound nodes 100",
+			expected: Info{Depth: 8, MultiPV: 1, Score: search.Score{Centipawns: 15}, LowerBound: true, Nodes: 100},
+			ok:       true,
+		},
+		{line: "info depth 8 currmove e2e4 currmovenumber 1", ok: false},
+		{line: "info string NNUE evaluation enabled", ok: false},
+		{line: "info score wdl 1 2", ok: false},
+		{line: "bestmove e2e4", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			info, ok := parseInfo(tc.line)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.expected, info)
+			}
+		})
+	}
+}
+
+func Test_goCommand(t *testing.T) {
+	assert.Equal(t, "go infinite", goCommand(search.Limits{}))
+	assert.Equal(t, "go depth 10 nodes 500 movetime 1500", goCommand(search.Limits{Depth: 10, Nodes: 500, MoveTime: 1500 * time.Millisecond}))
+}
@@ -0,0 +1,120 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// environment variable that makes the test binary run as a fake engine
+const fakeEngineEnv = "UCI_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEngineEnv) == "1" {
+		runFakeEngine(os.Stdin, os.Stdout)
+		os.Exit(0)
+	}
+
+	// engines started by tests are this binary running as a fake engine
+	os.Setenv(fakeEngineEnv, "1")
+	os.Exit(m.Run())
+}
+
+// runFakeEngine answers UCI commands with made up searches, so clients can be tested
+// without a real engine. its best move is always e2e4 and every depth takes 10ms.
+// setoption name Crash makes it exit on next go, and setoption name BadMultiPV
+// makes it send lines of multipv 0 and of more lines than asked for.
+func runFakeEngine(in io.Reader, out io.Writer) {
+	multiPV, crash, badMultiPV := 1, false, false
+	stop := make(chan struct{}, 1)
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			commands <- scanner.Text()
+		}
+		close(commands)
+	}()
+
+	for command := range commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "uci":
+			fmt.Fprintln(out, "id name Fake Engine 1.0")
+			fmt.Fprintln(out, "id author Chenizz tests")
+			fmt.Fprintln(out, "option name Hash type spin default 16 min 1 max 1024")
+			fmt.Fprintln(out, "option name MultiPV type spin default 1 min 1 max 5")
+			fmt.Fprintln(out, "uciok")
+		case "isready":
+			fmt.Fprintln(out, "readyok")
+		case "setoption":
+			switch {
+			case len(fields) == 5 && fields[2] == "MultiPV":
+				multiPV, _ = strconv.Atoi(fields[4])
+			case len(fields) >= 3 && fields[2] == "Crash":
+				crash = true
+			case len(fields) >= 3 && fields[2] == "BadMultiPV":
+				badMultiPV = true
+			}
+		case "go":
+			if crash {
+				os.Exit(3)
+			}
+
+			depth := 0
+			if len(fields) == 3 && fields[1] == "depth" {
+				depth, _ = strconv.Atoi(fields[2])
+			}
+
+			// a stop sent after the last search ended
+			select {
+			case <-stop:
+			default:
+			}
+
+			go fakeSearch(out, depth, multiPV, badMultiPV, stop)
+		case "stop":
+			select {
+			case stop <- struct{}{}:
+			default:
+			}
+		case "quit":
+			return
+		}
+	}
+}
+
+// sends info lines until depth is reached, forever if it is zero, or stop is received
+func fakeSearch(out io.Writer, depth, multiPV int, badMultiPV bool, stop chan struct{}) {
+	fmt.Fprintln(out, "info string searching")
+	for d := 1; depth == 0 || d <= depth; d++ {
+		select {
+		case <-stop:
+			fmt.Fprintln(out, "bestmove e2e4 ponder e7e5")
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		fmt.Fprintf(out, "info depth %d currmove e2e4 currmovenumber 1\n", d)
+		if badMultiPV {
+			fmt.Fprintf(out, "info depth %d multipv 0 score cp 99 pv d2d4\n", d)
+			fmt.Fprintf(out, "info depth %d multipv %d score cp 99 pv d2d4\n", d, multiPV+1)
+		}
+		for pv := 1; pv <= multiPV; pv++ {
+			fmt.Fprintf(out, "info depth %d seldepth %d multipv %d score cp %d nodes %d nps 100000 time %d pv e2e4 e7e5\n",
+				d, d+2, pv, 30-10*pv, d*1000, d*10)
+		}
+	}
+
+	fmt.Fprintln(out, "bestmove e2e4 ponder e7e5")
+}
@@ -0,0 +1,109 @@
+package uci
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"chenizz/internal/services/internal/search"
+)
+
+// Info is what an engine tells about its search in an info line with a score
+type Info struct {
+	Depth    int
+	SelDepth int
+
+	// line number when engine shows more than one best line, 1 is the best one
+	MultiPV int
+
+	Score search.Score
+
+	// true if score is only a bound of the real one, search was not finished
+	LowerBound bool
+	UpperBound bool
+
+	Nodes int
+	NPS   int
+	Time  time.Duration
+
+	// principal variation in UCI notation, like ["e2e4", "e7e5"]
+	PV []string
+}
+
+// parses an info line like "info depth 20 multipv 1 score cp 35 nodes 1000 pv e2e4 e7e5".
+// returns false for lines without a score, like currmove or string ones.
+func parseInfo(line string) (Info, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != "info" {
+		return Info{}, false
+	}
+
+	info := Info{MultiPV: 1}
+	hasScore := false
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case "string":
+			// rest of the line is free text
+			return info, hasScore
+		case "pv":
+			info.PV = append([]string(nil), fields[i+1:]...)
+			return info, hasScore
+		case "lowerbound":
+			info.LowerBound = true
+			continue
+		case "upperbound":
+			info.UpperBound = true
+			continue
+		case "score":
+			if i+2 >= len(fields) {
+				return info, false
+			}
+
+			n, err := strconv.Atoi(fields[i+2])
+			if err != nil {
+				return info, false
+			}
+
+			switch fields[i+1] {
+			case "cp":
+				info.Score = search.Score{Centipawns: n}
+			case "mate":
+				info.Score = search.Score{IsMate: true, Mate: n}
+			default:
+				return info, false
+			}
+
+			hasScore = true
+			i += 2
+			continue
+		}
+
+		if i+1 >= len(fields) {
+			break
+		}
+
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			// values of unknown fields, like currmove e2e4
+			continue
+		}
+
+		switch fields[i] {
+		case "depth":
+			info.Depth = n
+		case "seldepth":
+			info.SelDepth = n
+		case "multipv":
+			info.MultiPV = n
+		case "nodes":
+			info.Nodes = n
+		case "nps":
+			info.NPS = n
+		case "time":
+			info.Time = time.Duration(n) * time.Millisecond
+		}
+		i++
+	}
+
+	return info, hasScore
+}
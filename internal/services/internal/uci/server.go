@@ -0,0 +1,361 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chenizz/internal/services/internal/chess"
+	"chenizz/internal/services/internal/evaluation"
+	"chenizz/internal/services/internal/search"
+)
+
+type (
+	// Server plays the engine side of UCI protocol, so the built-in search
+	// can be used by chess GUIs like Arena or CuteChess.
+	Server struct {
+		out    io.Writer
+		outMu  sync.Mutex
+		engine *search.Engine
+		board  chess.Board
+
+		hash     int
+		chess960 bool
+
+		cancel    context.CancelFunc
+		searching sync.WaitGroup
+	}
+
+	// parameters of a go command
+	goParams struct {
+		limits   search.Limits
+		infinite bool
+
+		// remaining time and increment of each player, movesToGo is zero in sudden death
+		times, increments [2]time.Duration
+		movesToGo         int
+	}
+)
+
+const (
+	engineName   = "Chenizz"
+	engineAuthor = "Chenizz developers"
+
+	defaultHash = 16
+	maxHash     = 1024
+
+	// moves left assumed in sudden death games
+	defaultMovesToGo = 30
+
+	// time kept to answer in case of GUI or process delays
+	moveOverhead = 50 * time.Millisecond
+)
+
+// NewServer returns a server that writes its answers to out
+func NewServer(out io.Writer) *Server {
+	return &Server{
+		out:    out,
+		engine: search.NewEngine(evaluation.NewEvaluator(evaluation.DefaultWeights()), defaultHash),
+		board:  chess.NewBoard(),
+		hash:   defaultHash,
+	}
+}
+
+// Serve reads commands from in until quit is received or in is closed,
+// a search in progress is stopped before returning.
+func (s *Server) Serve(in io.Reader) error {
+	defer s.stop()
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "quit" {
+			return nil
+		}
+
+		s.handle(fields[0], fields[1:])
+	}
+
+	return scanner.Err()
+}
+
+func (s *Server) handle(command string, args []string) {
+	switch command {
+	case "uci":
+		s.send("id name %s", engineName)
+		s.send("id author %s", engineAuthor)
+		s.send("option name Hash type spin default %d min 1 max %d", defaultHash, maxHash)
+		s.send("option name Clear Hash type button")
+		s.send("option name UCI_Chess960 type check default false")
+		s.send("option name EvalFile type string default <empty>")
+		s.send("uciok")
+	case "isready":
+		s.send("readyok")
+	case "ucinewgame":
+		s.stop()
+		s.engine.NewGame()
+		s.board = chess.NewBoard()
+	case "setoption":
+		s.stop()
+		s.setOption(args)
+	case "position":
+		s.stop()
+		s.position(args)
+	case "go":
+		s.stop()
+		s.goSearch(parseGo(args))
+	case "stop":
+		s.stop()
+	default:
+		s.send("info string unknown command %s", command)
+	}
+}
+
+// setoption name <name> [value <value>], option names can have spaces
+func (s *Server) setOption(args []string) {
+	name, value := "", ""
+	for i, arg := range args {
+		if arg == "value" {
+			name = strings.Join(args[1:i], " ")
+			value = strings.Join(args[i+1:], " ")
+			break
+		}
+	}
+
+	if name == "" && len(args) > 1 {
+		name = strings.Join(args[1:], " ")
+	}
+
+	switch strings.ToLower(name) {
+	case "hash":
+		mb, err := strconv.Atoi(value)
+		if err != nil || mb < 1 || mb > maxHash {
+			s.send("info string invalid Hash value %q", value)
+			return
+		}
+
+		s.hash = mb
+		s.engine.SetHashSize(mb)
+	case "clear hash":
+		s.engine.NewGame()
+	case "uci_chess960":
+		s.chess960 = value == "true"
+		s.board.SetChess960(s.chess960)
+	case "evalfile":
+		weights := evaluation.DefaultWeights()
+		if value != "" && value != "<empty>" {
+			var err error
+			weights, err = evaluation.LoadWeights(value)
+			if err != nil {
+				s.send("info string %s", err)
+				return
+			}
+		}
+
+		s.engine = search.NewEngine(evaluation.NewEvaluator(weights), s.hash)
+	default:
+		s.send("info string unknown option %q", name)
+	}
+}
+
+// position startpos|fen <fen> [moves <move>...]
+func (s *Server) position(args []string) {
+	if len(args) == 0 {
+		s.send("info string position needs startpos or fen")
+		return
+	}
+
+	moves := len(args)
+	for i, arg := range args {
+		if arg == "moves" {
+			moves = i
+			break
+		}
+	}
+
+	board := chess.NewBoard()
+	board.SetChess960(s.chess960)
+	switch args[0] {
+	case "startpos":
+	case "fen":
+		if err := board.TranslateFEN(strings.Join(args[1:moves], " ")); err != nil {
+			s.send("info string invalid fen: %s", err)
+			return
+		}
+	default:
+		s.send("info string position needs startpos or fen")
+		return
+	}
+
+	for i := moves + 1; i < len(args); i++ {
+		if _, err := board.ParseMove(args[i]); err != nil {
+			s.send("info string %s", err)
+			break
+		}
+
+		board.MakeMove(args[i])
+	}
+
+	s.board = board
+}
+
+func parseGo(args []string) goParams {
+	params := goParams{}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "infinite" {
+			params.infinite = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+
+		n, err := strconv.Atoi(args[i+1])
+		if err != nil {
+			continue
+		}
+
+		ms := time.Duration(n) * time.Millisecond
+		switch args[i] {
+		case "depth":
+			params.limits.Depth = n
+		case "nodes":
+			params.limits.Nodes = n
+		case "movetime":
+			params.limits.MoveTime = ms
+		case "wtime":
+			params.times[0] = ms
+		case "btime":
+			params.times[1] = ms
+		case "winc":
+			params.increments[0] = ms
+		case "binc":
+			params.increments[1] = ms
+		case "movestogo":
+			params.movesToGo = n
+		default:
+			continue
+		}
+		i++
+	}
+
+	return params
+}
+
+// time to spend in this move given the clock of side to move
+func (p goParams) moveTime(turn string) time.Duration {
+	side := 0
+	if turn == "b" {
+		side = 1
+	}
+
+	remaining := p.times[side]
+	if remaining <= 0 {
+		return 0
+	}
+
+	movesToGo := p.movesToGo
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+
+	budget := remaining/time.Duration(movesToGo) + p.increments[side]*3/4
+	if budget > remaining-moveOverhead {
+		budget = remaining - moveOverhead
+	}
+
+	if budget < time.Millisecond {
+		return time.Millisecond
+	}
+
+	return budget
+}
+
+// starts a search that sends info lines while it runs and bestmove when it ends.
+// an infinite search does not send bestmove until it is stopped.
+func (s *Server) goSearch(params goParams) {
+	limits := params.limits
+	if limits.MoveTime == 0 && !params.infinite {
+		limits.MoveTime = params.moveTime(s.board.Turn)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.engine.OnIteration = s.info
+
+	s.searching.Add(1)
+	go func(board chess.Board) {
+		defer s.searching.Done()
+
+		result := s.engine.Search(ctx, board, limits)
+		if params.infinite {
+			<-ctx.Done()
+		}
+
+		// game is over
+		if result.Depth == 0 {
+			s.send("bestmove 0000")
+			return
+		}
+
+		if len(result.PV) > 1 {
+			s.send("bestmove %s ponder %s", FormatMove(result.BestMove), FormatMove(result.PV[1]))
+			return
+		}
+
+		s.send("bestmove %s", FormatMove(result.BestMove))
+	}(s.board)
+}
+
+// stops search in progress, if any, and waits until its bestmove is sent
+func (s *Server) stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.searching.Wait()
+	s.cancel = nil
+}
+
+func (s *Server) info(r search.Result) {
+	nps := 0
+	if r.Time > 0 {
+		nps = int(float64(r.Nodes) / r.Time.Seconds())
+	}
+
+	pv := make([]string, 0, len(r.PV))
+	for _, m := range r.PV {
+		pv = append(pv, FormatMove(m))
+	}
+
+	s.send("info depth %d score %s nodes %d nps %d time %d pv %s",
+		r.Depth, r.Score, r.Nodes, nps, r.Time.Milliseconds(), strings.Join(pv, " "))
+}
+
+func (s *Server) send(format string, args ...interface{}) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+// FormatMove writes m in UCI notation: promotions in lowercase like "e7e8q",
+// drops keep the piece in uppercase like "N@f3"
+func FormatMove(m chess.Move) string {
+	if m.IsDrop() {
+		return m.String()
+	}
+
+	return strings.ToLower(m.String())
+}
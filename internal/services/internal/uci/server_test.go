@@ -0,0 +1,244 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// a server connected through pipes, like a GUI runs an engine
+type session struct {
+	t     *testing.T
+	in    *io.PipeWriter
+	lines chan string
+	done  chan error
+}
+
+func newSession(t *testing.T) *session {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	s := &session{t: t, in: inW, lines: make(chan string, 1000), done: make(chan error, 1)}
+	go func() {
+		s.done <- NewServer(outW).Serve(inR)
+		outW.Close()
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			s.lines <- scanner.Text()
+		}
+		close(s.lines)
+	}()
+
+	return s
+}
+
+func (s *session) send(command string) {
+	fmt.Fprintln(s.in, command)
+}
+
+// returns lines sent by server until one starting with prefix, that one included
+func (s *session) readUntil(prefix string) []string {
+	lines := []string{}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-s.lines:
+			if !ok {
+				s.t.Fatalf("server closed output waiting for %q, got %v", prefix, lines)
+			}
+
+			lines = append(lines, line)
+			if strings.HasPrefix(line, prefix) {
+				return lines
+			}
+		case <-timeout:
+			s.t.Fatalf("timeout waiting for %q, got %v", prefix, lines)
+		}
+	}
+}
+
+// returns true if server sends nothing during d
+func (s *session) isQuiet(d time.Duration) bool {
+	select {
+	case line := <-s.lines:
+		s.t.Logf("unexpected line %q", line)
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func (s *session) quit() {
+	s.send("quit")
+	select {
+	case err := <-s.done:
+		assert.Nil(s.t, err)
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("server did not quit")
+	}
+}
+
+func Test_Server_Handshake(t *testing.T) {
+	s := newSession(t)
+
+	s.send("uci")
+	lines := s.readUntil("uciok")
+	assert.Equal(t, "id name Chenizz", lines[0])
+	assert.Contains(t, lines, "option name Hash type spin default 16 min 1 max 1024")
+	assert.Contains(t, lines, "option name UCI_Chess960 type check default false")
+
+	s.send("isready")
+	assert.Equal(t, []string{"readyok"}, s.readUntil("readyok"))
+
+	s.quit()
+}
+
+func Test_Server_GoDepth(t *testing.T) {
+	s := newSession(t)
+
+	s.send("position startpos moves e2e4 e7e5 g1f3")
+	s.send("go depth 3")
+	lines := s.readUntil("bestmove")
+
+	assert.Len(t, lines, 4)
+	assert.Regexp(t, `^info depth 3 score cp -?\d+ nodes \d+ nps \d+ time \d+ pv \w+`, lines[2])
+	assert.Regexp(t, `^bestmove [a-h][1-8][a-h][1-8] ponder [a-h][1-8][a-h][1-8]$`, lines[3])
+
+	s.quit()
+}
+
+func Test_Server_FindsMate(t *testing.T) {
+	s := newSession(t)
+
+	s.send("position fen 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	s.send("go depth 5")
+	lines := s.readUntil("bestmove")
+
+	assert.Contains(t, lines[len(lines)-2], "score mate 1")
+	assert.Equal(t, "bestmove a1a8", lines[len(lines)-1])
+
+	// promotions are written in lowercase
+	s.send("position fen 8/1P6/8/8/8/8/k7/4K3 w - - 0 1 moves b7b8q a2a3")
+	s.send("go depth 1")
+	lines = s.readUntil("bestmove")
+	assert.Contains(t, lines[0], "score cp")
+
+	s.send("position fen 8/1P6/8/8/8/8/k7/4K3 w - - 0 1")
+	s.send("go depth 2")
+	lines = s.readUntil("bestmove")
+	assert.Equal(t, "bestmove b7b8q ponder a2a3", lines[len(lines)-1])
+
+	s.quit()
+}
+
+func Test_Server_GameOver(t *testing.T) {
+	s := newSession(t)
+
+	s.send("position fen R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1")
+	s.send("go depth 3")
+	assert.Equal(t, []string{"bestmove 0000"}, s.readUntil("bestmove"))
+
+	s.quit()
+}
+
+func Test_Server_InfiniteAndStop(t *testing.T) {
+	s := newSession(t)
+
+	// search ends at once but bestmove waits for stop
+	s.send("position fen R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1")
+	s.send("go infinite")
+	assert.True(t, s.isQuiet(100*time.Millisecond))
+
+	s.send("stop")
+	assert.Equal(t, []string{"bestmove 0000"}, s.readUntil("bestmove"))
+
+	s.send("position startpos")
+	s.send("go infinite")
+	time.Sleep(50 * time.Millisecond)
+	s.send("stop")
+	lines := s.readUntil("bestmove")
+	assert.Regexp(t, `^bestmove \w+`, lines[len(lines)-1])
+
+	s.quit()
+}
+
+func Test_Server_TimeControls(t *testing.T) {
+	s := newSession(t)
+
+	start := time.Now()
+	s.send("position startpos")
+	s.send("go movetime 100")
+	s.readUntil("bestmove")
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	s.send("position startpos moves e2e4")
+	s.send("go wtime 1000 btime 1000 winc 0 binc 0")
+	s.readUntil("bestmove")
+	assert.Less(t, time.Since(start), time.Second)
+
+	s.send("go nodes 3000")
+	s.readUntil("bestmove")
+
+	s.quit()
+}
+
+func Test_Server_Errors(t *testing.T) {
+	s := newSession(t)
+
+	s.send("position startpos moves e2e4 e7e5 e1e3")
+	assert.Equal(t, []string{"info string e1e3 is not a legal move"}, s.readUntil("info"))
+
+	s.send("position fen 8/8/8/8/8/8/8/8 w - - 0 1")
+	assert.Contains(t, s.readUntil("info")[0], "info string invalid fen")
+
+	s.send("setoption name Hash value 0")
+	assert.Equal(t, []string{`info string invalid Hash value "0"`}, s.readUntil("info"))
+
+	s.send("setoption name Threads value 2")
+	assert.Equal(t, []string{`info string unknown option "Threads"`}, s.readUntil("info"))
+
+	s.send("setoption name EvalFile value missing.json")
+	assert.Contains(t, s.readUntil("info")[0], "missing.json")
+
+	s.send("flip")
+	assert.Equal(t, []string{"info string unknown command flip"}, s.readUntil("info"))
+
+	s.send("setoption name Hash value 32")
+	s.send("setoption name Clear Hash")
+	s.send("ucinewgame")
+	s.send("isready")
+	assert.Equal(t, []string{"readyok"}, s.readUntil("readyok"))
+
+	s.quit()
+}
+
+func Test_Server_Chess960(t *testing.T) {
+	s := newSession(t)
+
+	s.send("setoption name UCI_Chess960 value true")
+	s.send("position fen 4k3/8/8/8/8/8/8/4K2R w K - 0 1 moves e1h1")
+	s.send("go depth 1")
+	lines := s.readUntil("bestmove")
+	assert.Contains(t, lines[0], "info depth 1")
+
+	s.quit()
+}
+
+func Test_Server_ClosedInput(t *testing.T) {
+	s := newSession(t)
+
+	s.send("go infinite")
+	s.in.Close()
+
+	s.readUntil("bestmove")
+	assert.Nil(t, <-s.done)
+}
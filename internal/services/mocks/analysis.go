@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"chenizz/internal/viewmodels"
+)
+
+type AnalysisServiceMock struct {
+	response viewmodels.GameAnalysisResponse
+	err      error
+}
+
+func (a *AnalysisServiceMock) PatchAnalyzeGame(r viewmodels.GameAnalysisResponse, err error) {
+	a.response = r
+	a.err = err
+}
+
+func (a AnalysisServiceMock) AnalyzeGame(ctx context.Context, pgn string, depth int) (viewmodels.GameAnalysisResponse, error) {
+	return a.response, a.err
+}
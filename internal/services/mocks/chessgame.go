@@ -12,6 +12,6 @@ func (c *ChessGameServiceMock) PatchMakeMove(r viewmodels.ChessGameResponse, err
 	c.err = err
 }
 
-func (c ChessGameServiceMock) MakeMove(move string, fen string) (viewmodels.ChessGameResponse, error) {
+func (c ChessGameServiceMock) MakeMove(move string, fen string, variant string, history ...string) (viewmodels.ChessGameResponse, error) {
 	return c.response, c.err
 }
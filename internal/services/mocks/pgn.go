@@ -0,0 +1,17 @@
+package mocks
+
+import "chenizz/internal/viewmodels"
+
+type PGNServiceMock struct {
+	response viewmodels.PGNResponse
+	err      error
+}
+
+func (p *PGNServiceMock) PatchParsePGN(r viewmodels.PGNResponse, err error) {
+	p.response = r
+	p.err = err
+}
+
+func (p PGNServiceMock) ParsePGN(pgn string) (viewmodels.PGNResponse, error) {
+	return p.response, p.err
+}
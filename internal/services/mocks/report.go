@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"chenizz/internal/viewmodels"
+)
+
+type ReportServiceMock struct {
+	response viewmodels.PlayerReportResponse
+	err      error
+}
+
+func (r *ReportServiceMock) PatchPlayerReport(resp viewmodels.PlayerReportResponse, err error) {
+	r.response = resp
+	r.err = err
+}
+
+func (r ReportServiceMock) PlayerReport(ctx context.Context, user string, days, depth int) (viewmodels.PlayerReportResponse, error) {
+	return r.response, r.err
+}
+
+// writes player name and group, so tests can check what controller asked for
+func (r ReportServiceMock) WriteCSV(w io.Writer, report viewmodels.PlayerReportResponse, group string) error {
+	_, err := fmt.Fprintf(w, "%s,%s\n", report.Player, group)
+	return err
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"chenizz/internal/services/internal/pgn"
+	"chenizz/internal/viewmodels"
+)
+
+type PGNService struct{}
+
+// ParsePGN returns every game of a plain-text PGN with its tags, main line moves in UCI format
+// and move tree with comments, NAGs and variations. games that are not valid are
+// left out and their errors are returned with the others.
+func (PGNService) ParsePGN(pgnText string) (viewmodels.PGNResponse, error) {
+	games, err := pgn.ParseGames(strings.NewReader(pgnText))
+
+	var parseErrors pgn.ParseErrors
+	if err != nil && !errors.As(err, &parseErrors) {
+		return viewmodels.PGNResponse{}, fmt.Errorf("error calling pgn.ParseGames: %w", err)
+	}
+
+	response := viewmodels.PGNResponse{
+		Games:  make([]viewmodels.PGNGameResponse, 0, len(games)),
+		Errors: make([]viewmodels.PGNErrorResponse, 0, len(parseErrors)),
+	}
+
+	for _, g := range games {
+		response.Games = append(response.Games, viewmodels.PGNGameResponse{
+			Event:       g.Event,
+			Site:        g.Site,
+			Date:        g.Date,
+			White:       g.White,
+			Black:       g.Black,
+			Result:      g.Result,
+			Variant:     g.Variant,
+			TimeControl: g.TimeControl,
+			ECO:         g.ECO,
+			FEN:         g.FEN,
+			Tags:        tagsResponse(g.Tags),
+			Moves:       g.UCIFormatMoves,
+			Tree:        moveNodeResponse(g.Tree),
+		})
+	}
+
+	for _, e := range parseErrors {
+		response.Errors = append(response.Errors, viewmodels.PGNErrorResponse{
+			Game:    e.Game,
+			Line:    e.Line,
+			Column:  e.Column,
+			Message: e.Err.Error(),
+		})
+	}
+
+	return response, nil
+}
+
+func tagsResponse(tags pgn.Tags) []viewmodels.TagResponse {
+	response := make([]viewmodels.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		response = append(response, viewmodels.TagResponse{Name: tag.Name, Value: tag.Value})
+	}
+
+	return response
+}
+
+func moveNodeResponse(n *pgn.Node) viewmodels.MoveNodeResponse {
+	response := viewmodels.MoveNodeResponse{
+		SAN:              n.SAN,
+		UCI:              n.UCI,
+		FEN:              n.FEN,
+		StartingComments: n.StartingComments,
+		Comments:         n.Comments,
+		NAGs:             n.NAGs,
+	}
+
+	for _, child := range n.Children {
+		response.Children = append(response.Children, moveNodeResponse(child))
+	}
+
+	return response
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chenizz/internal/viewmodels"
+)
+
+func Test_ParsePGN(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	games := `[Event "Lesson"]
+[White "alice"]
+
+1. e4 {center} e5 (1... c5 $1) 2. Nf3 *
+
+[Event "Illegal"]
+
+1. e4 e4 *`
+
+	p := PGNService{}
+
+	// Act
+	r, err := p.ParsePGN(games)
+
+	// Assert
+	assert.Nil(err)
+	assert.Len(r.Games, 1)
+	assert.Equal("alice", r.Games[0].White)
+	assert.Equal([]viewmodels.TagResponse{{Name: "Event", Value: "Lesson"}, {Name: "White", Value: "alice"}}, r.Games[0].Tags)
+	assert.Equal([]string{"e2e4", "e7e5", "g1f3"}, r.Games[0].Moves)
+
+	e4 := r.Games[0].Tree.Children[0]
+	assert.Equal("e4", e4.SAN)
+	assert.Equal([]string{"center"}, e4.Comments)
+	assert.Len(e4.Children, 2)
+	assert.Equal(viewmodels.MoveNodeResponse{
+		SAN:  "c5",
+		UCI:  "c7c5",
+		FEN:  "rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+		NAGs: []int{1},
+	}, e4.Children[1])
+
+	assert.Equal([]viewmodels.PGNErrorResponse{
+		{Game: 2, Line: 8, Column: 7, Message: "error calling board.ParseSAN: e4 is not a legal move"},
+	}, r.Errors)
+}
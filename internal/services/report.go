@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"chenizz/internal/interfaces"
+	"chenizz/internal/services/internal/analysis"
+	"chenizz/internal/services/internal/pgn"
+	platforms "chenizz/internal/services/internal/platforms"
+	"chenizz/internal/viewmodels"
+)
+
+type ReportService struct {
+	Platform platforms.ChessPlatform
+
+	// UCI engine that evaluates positions, the built-in search is used if it is empty
+	EnginePath string
+
+	// engine processes analyzing games at the same time, one if it is zero
+	Engines int
+
+	// most games a report analyzes, defaultMaxReportGames if it is zero
+	MaxGames int
+}
+
+// NewLichessReportService returns a service that analyzes games from Lichess,
+// with the UCI engine at enginePath or the built-in search if it is empty,
+// running engines processes at the same time.
+func NewLichessReportService(enginePath string, engines int) ReportService {
+	return ReportService{Platform: platforms.NewLichessPlatform(), EnginePath: enginePath, Engines: engines}
+}
+
+const (
+	// games are analyzed in the request, so a report can not have many
+	defaultMaxReportGames = 100
+)
+
+// PlayerReport analyzes games user played in the last days and returns the ACPL
+// and accuracy of each one, and their averages by time control and by week.
+// positions are searched until depth, a default one is used if it is not positive.
+// games that can not be parsed or analyzed, like the ones of variants, are counted as skipped,
+// and only the first MaxGames games are read.
+func (r ReportService) PlayerReport(ctx context.Context, user string, days, depth int) (viewmodels.PlayerReportResponse, error) {
+	stream, err := r.Platform.GetGamesFromManyDays(ctx, user, days)
+	if err != nil {
+		return viewmodels.PlayerReportResponse{}, fmt.Errorf("error calling GetGamesFromManyDays: %w", err)
+	}
+	defer stream.Close()
+
+	maxGames := r.MaxGames
+	if maxGames <= 0 {
+		maxGames = defaultMaxReportGames
+	}
+
+	evaluator, closeEvaluator, err := gameEvaluator(ctx, r.EnginePath, r.Engines, depth)
+	if err != nil {
+		return viewmodels.PlayerReportResponse{}, err
+	}
+	defer closeEvaluator()
+
+	// the built-in search analyzes a game at a time, engines one each
+	workers := 1
+	if r.EnginePath != "" && r.Engines > 1 {
+		workers = r.Engines
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// games are analyzed while the stream is read, so only the ones being analyzed are kept
+	games := make(chan readGame)
+	var readSkipped int
+	var readErr error
+	go func() {
+		defer close(games)
+		readSkipped, readErr = readGames(ctx, stream, maxGames, games)
+	}()
+
+	var mu sync.Mutex
+	builder, skipped := analysis.NewPlayerReportBuilder(user), 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range games {
+				report, err := analysis.AnalyzeGame(ctx, evaluator, g.game)
+
+				mu.Lock()
+				if err != nil {
+					skipped++
+				} else {
+					builder.Add(g.index, g.game, report)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if readErr != nil {
+		return viewmodels.PlayerReportResponse{}, readErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return viewmodels.PlayerReportResponse{}, err
+	}
+
+	return playerReportResponse(builder.Build(), skipped+readSkipped), nil
+}
+
+// a valid game of a PGN stream and how many valid games were read before it
+type readGame struct {
+	index int
+	game  pgn.PGN
+}
+
+// reads up to maxGames games of a PGN stream one at a time and sends them to games,
+// games that are not valid are counted as skipped. it stops when ctx is done.
+func readGames(ctx context.Context, stream io.Reader, maxGames int, games chan<- readGame) (int, error) {
+	reader := pgn.NewReader(stream)
+
+	skipped := 0
+	for read := 0; read < maxGames; {
+		game, err := reader.Read()
+		if err == io.EOF {
+			return skipped, nil
+		}
+
+		var parseErr *pgn.ParseError
+		if errors.As(err, &parseErr) {
+			skipped++
+			continue
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("error calling reader.Read: %w", err)
+		}
+
+		select {
+		case games <- readGame{index: read, game: game}:
+		case <-ctx.Done():
+			return skipped, ctx.Err()
+		}
+		read++
+	}
+
+	return skipped, nil
+}
+
+// WriteCSV writes report as CSV with a row by game or by group of games,
+// group is interfaces.CSVGroupGames, interfaces.CSVGroupTimeControl or interfaces.CSVGroupWeek.
+func (r ReportService) WriteCSV(w io.Writer, report viewmodels.PlayerReportResponse, group string) error {
+	rows := [][]string{}
+	switch group {
+	case interfaces.CSVGroupGames, "":
+		rows = append(rows, []string{"site", "date", "week", "time_control", "color", "opponent", "result", "moves", "acpl", "accuracy"})
+		for _, g := range report.Games {
+			rows = append(rows, []string{g.Site, g.Date, g.Week, g.TimeControl, g.Color, g.Opponent, g.Result,
+				strconv.Itoa(g.Moves), formatFloat(g.ACPL), formatFloat(g.Accuracy)})
+		}
+	case interfaces.CSVGroupTimeControl:
+		rows = aggregateRows(group, report.ByTimeControl)
+	case interfaces.CSVGroupWeek:
+		rows = aggregateRows(group, report.ByWeek)
+	default:
+		return fmt.Errorf("csv group %q is not supported", group)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("error calling writer.WriteAll: %w", err)
+	}
+
+	return nil
+}
+
+func aggregateRows(group string, aggregates []viewmodels.AggregateResponse) [][]string {
+	rows := [][]string{{group, "games", "acpl", "accuracy"}}
+	for _, a := range aggregates {
+		rows = append(rows, []string{a.Key, strconv.Itoa(a.Games), formatFloat(a.ACPL), formatFloat(a.Accuracy)})
+	}
+
+	return rows
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
+
+func playerReportResponse(r analysis.PlayerReport, skipped int) viewmodels.PlayerReportResponse {
+	response := viewmodels.PlayerReportResponse{
+		Player:        r.Player,
+		Games:         make([]viewmodels.GameReportResponse, 0, len(r.Games)),
+		ByTimeControl: aggregateResponses(r.ByTimeControl),
+		ByWeek:        aggregateResponses(r.ByWeek),
+		SkippedGames:  skipped,
+	}
+
+	for _, g := range r.Games {
+		response.Games = append(response.Games, viewmodels.GameReportResponse{
+			Site:        g.Site,
+			Date:        g.Date,
+			Week:        g.Week,
+			TimeControl: g.TimeControl,
+			Color:       g.Color,
+			Opponent:    g.Opponent,
+			Result:      g.Result,
+			Moves:       g.Moves,
+			ACPL:        g.ACPL,
+			Accuracy:    g.Accuracy,
+		})
+	}
+
+	return response
+}
+
+func aggregateResponses(aggregates []analysis.Aggregate) []viewmodels.AggregateResponse {
+	responses := make([]viewmodels.AggregateResponse, 0, len(aggregates))
+	for _, a := range aggregates {
+		responses = append(responses, viewmodels.AggregateResponse{Key: a.Key, Games: a.Games, ACPL: a.ACPL, Accuracy: a.Accuracy})
+	}
+
+	return responses
+}
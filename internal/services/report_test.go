@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"chenizz/internal/interfaces"
+	"chenizz/internal/viewmodels"
+)
+
+type platformMock struct {
+	games string
+	err   error
+}
+
+func (p platformMock) GetGamesFromManyDays(ctx context.Context, user string, daysAgo int) (io.ReadCloser, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(strings.NewReader(p.games)), nil
+}
+
+const reportGames = `[Event "Rated Blitz game"]
+[Site "https://lichess.org/g1"]
+[Date "2024.02.20"]
+[White "alice"]
+[Black "bob"]
+[Result "0-1"]
+[TimeControl "180+0"]
+
+1. f3 e5 2. g4 Qh4# 0-1
+
+[Event "Rated Rapid game"]
+[Site "https://lichess.org/g2"]
+[Date "2024.02.12"]
+[White "carol"]
+[Black "alice"]
+[Result "1-0"]
+[TimeControl "600+0"]
+
+1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# 1-0
+
+[Event "Rated Atomic game"]
+[Site "https://lichess.org/g3"]
+[Date "2024.02.13"]
+[White "alice"]
+[Black "dave"]
+[Result "1-0"]
+[Variant "Atomic"]
+
+1. e4 e5 1-0
+`
+
+func Test_PlayerReport(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	r := ReportService{Platform: platformMock{games: reportGames}}
+
+	// Act
+	resp, err := r.PlayerReport(context.Background(), "alice", 30, 2)
+
+	// Assert
+	assert.Nil(err)
+	assert.Equal("alice", resp.Player)
+	assert.Equal(1, resp.SkippedGames)
+	assert.Len(resp.Games, 2)
+
+	assert.Equal("https://lichess.org/g2", resp.Games[0].Site)
+	assert.Equal("b", resp.Games[0].Color)
+	assert.Equal("carol", resp.Games[0].Opponent)
+	assert.Equal(3, resp.Games[0].Moves)
+	assert.Equal("2024-W08", resp.Games[1].Week)
+	assert.Equal(2, resp.Games[1].Moves)
+
+	assert.Equal([]string{"600+0", "180+0"}, []string{resp.ByTimeControl[0].Key, resp.ByTimeControl[1].Key})
+	assert.Equal([]string{"2024-W07", "2024-W08"}, []string{resp.ByWeek[0].Key, resp.ByWeek[1].Key})
+}
+
+func Test_PlayerReport_InvalidGame(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	games := `[Event "Rated Blitz game"]
+[White "alice"]
+[Black "bob"]
+
+1. e4 Ke7 Ke3 1-0
+
+` + reportGames
+	r := ReportService{Platform: platformMock{games: games}}
+
+	// Act
+	resp, err := r.PlayerReport(context.Background(), "alice", 30, 2)
+
+	// Assert
+	assert.Nil(err)
+	assert.Equal(2, resp.SkippedGames)
+	assert.Len(resp.Games, 2)
+}
+
+func Test_PlayerReport_MaxGames(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	r := ReportService{Platform: platformMock{games: reportGames}, MaxGames: 1}
+
+	// Act
+	resp, err := r.PlayerReport(context.Background(), "alice", 30, 2)
+
+	// Assert
+	assert.Nil(err)
+	assert.Len(resp.Games, 1)
+	assert.Equal("https://lichess.org/g1", resp.Games[0].Site)
+	assert.Equal(0, resp.SkippedGames)
+}
+
+func Test_PlayerReport_PlatformError(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	r := ReportService{Platform: platformMock{err: fmt.Errorf("lichess is down")}}
+
+	// Act
+	_, err := r.PlayerReport(context.Background(), "alice", 30, 2)
+
+	// Assert
+	assert.EqualError(err, "error calling GetGamesFromManyDays: lichess is down")
+}
+
+func Test_PlayerReport_PassesContextToPlatform(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	r := ReportService{Platform: platformMock{games: reportGames}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := r.PlayerReport(ctx, "alice", 30, 2)
+
+	// Assert
+	assert.EqualError(err, "error calling GetGamesFromManyDays: context canceled")
+}
+
+func Test_WriteCSV(t *testing.T) {
+	// Arrange
+	assert := assert.New(t)
+	report := viewmodels.PlayerReportResponse{
+		Player: "alice",
+		Games: []viewmodels.GameReportResponse{
+			{Site: "g1", Date: "2024.02.20", Week: "2024-W08", TimeControl: "180+0", Color: "w", Opponent: "bob, jr", Result: "0-1", Moves: 2, ACPL: 512.5, Accuracy: 31},
+		},
+		ByWeek: []viewmodels.AggregateResponse{{Key: "2024-W08", Games: 1, ACPL: 512.5, Accuracy: 31}},
+	}
+	r := ReportService{}
+
+	// Act
+	games, weeks, invalid := bytes.Buffer{}, bytes.Buffer{}, bytes.Buffer{}
+	errGames := r.WriteCSV(&games, report, interfaces.CSVGroupGames)
+	errWeeks := r.WriteCSV(&weeks, report, interfaces.CSVGroupWeek)
+	errInvalid := r.WriteCSV(&invalid, report, "month")
+
+	// Assert
+	assert.Nil(errGames)
+	assert.Equal("site,date,week,time_control,color,opponent,result,moves,acpl,accuracy\n"+
+		"g1,2024.02.20,2024-W08,180+0,w,\"bob, jr\",0-1,2,512.5,31.0\n", games.String())
+
+	assert.Nil(errWeeks)
+	assert.Equal("week,games,acpl,accuracy\n2024-W08,1,512.5,31.0\n", weeks.String())
+
+	assert.EqualError(errInvalid, `csv group "month" is not supported`)
+	assert.Empty(invalid.String())
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"io"
+
+	"chenizz/internal/services/internal/uci"
+)
+
+// RunUCI runs the built-in engine with UCI protocol, reading GUI commands from in
+// and writing answers to out until quit is received or in is closed.
+func RunUCI(in io.Reader, out io.Writer) error {
+	return uci.NewServer(out).Serve(in)
+}
@@ -0,0 +1,37 @@
+package viewmodels
+
+type (
+	GameAnalysisResponse struct {
+		White PlayerAnalysisResponse `json:"white"`
+		Black PlayerAnalysisResponse `json:"black"`
+		Moves []MoveAnalysisResponse `json:"moves"`
+	}
+
+	PlayerAnalysisResponse struct {
+		Name            string         `json:"name"`
+		ACPL            float64        `json:"acpl"`
+		Accuracy        float64        `json:"accuracy"`
+		Classifications map[string]int `json:"classifications"`
+	}
+
+	MoveAnalysisResponse struct {
+		Ply            int          `json:"ply"`
+		Color          string       `json:"color"`
+		Move           string       `json:"move"`
+		SAN            string       `json:"san"`
+		BestMove       string       `json:"best_move"`
+		EvalBefore     EvalResponse `json:"eval_before"`
+		EvalAfter      EvalResponse `json:"eval_after"`
+		CentipawnLoss  int          `json:"centipawn_loss"`
+		WinChanceDrop  float64      `json:"win_chance_drop"`
+		Accuracy       float64      `json:"accuracy"`
+		Classification string       `json:"classification"`
+	}
+
+	// evaluation from white point of view
+	EvalResponse struct {
+		Centipawns int  `json:"centipawns"`
+		IsMate     bool `json:"is_mate"`
+		Mate       int  `json:"mate"`
+	}
+)
@@ -2,11 +2,19 @@ package viewmodels
 
 type (
 	ChessGameResponse struct {
-		MoveDone       bool     `json:"move_done"`
-		IsCheckMate    bool     `json:"is_checkmate"`
-		IsStaleMate    bool     `json:"is_stalemate"`
-		AvailableMoves []string `json:"available_moves"`
-		FEN            string   `json:"fen"`
+		MoveDone               bool     `json:"move_done"`
+		SAN                    string   `json:"san"`
+		IsCheckMate            bool     `json:"is_checkmate"`
+		IsStaleMate            bool     `json:"is_stalemate"`
+		IsThreefoldRepetition  bool     `json:"is_threefold_repetition"`
+		IsFivefoldRepetition   bool     `json:"is_fivefold_repetition"`
+		IsFiftyMoveRule        bool     `json:"is_fifty_move_rule"`
+		IsSeventyFiveMoveRule  bool     `json:"is_seventy_five_move_rule"`
+		IsInsufficientMaterial bool     `json:"is_insufficient_material"`
+		Outcome                string   `json:"outcome"`
+		Termination            string   `json:"termination"`
+		AvailableMoves         []string `json:"available_moves"`
+		FEN                    string   `json:"fen"`
 	}
 
 	ErrorResponse struct {
@@ -0,0 +1,50 @@
+package viewmodels
+
+type (
+	PGNResponse struct {
+		Games []PGNGameResponse `json:"games"`
+		// games that could not be parsed
+		Errors []PGNErrorResponse `json:"errors"`
+	}
+
+	PGNGameResponse struct {
+		Event       string           `json:"event"`
+		Site        string           `json:"site"`
+		Date        string           `json:"date"`
+		White       string           `json:"white"`
+		Black       string           `json:"black"`
+		Result      string           `json:"result"`
+		Variant     string           `json:"variant"`
+		TimeControl string           `json:"time_control"`
+		ECO         string           `json:"eco"`
+		FEN         string           `json:"fen"`
+		Tags        []TagResponse    `json:"tags"`
+		Moves       []string         `json:"moves"`
+		Tree        MoveNodeResponse `json:"tree"`
+	}
+
+	// tag pair of a game, tags are answered in the order they are written
+	TagResponse struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	// position of a game tree, root one is the starting position and has no move
+	MoveNodeResponse struct {
+		SAN              string             `json:"san,omitempty"`
+		UCI              string             `json:"uci,omitempty"`
+		FEN              string             `json:"fen"`
+		StartingComments []string           `json:"starting_comments,omitempty"`
+		Comments         []string           `json:"comments,omitempty"`
+		NAGs             []int              `json:"nags,omitempty"`
+		Children         []MoveNodeResponse `json:"children,omitempty"`
+	}
+
+	// game is its number in the PGN starting with 1, line and column are where the problem is
+	PGNErrorResponse struct {
+		Game    int    `json:"game"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+		Message string `json:"message"`
+	}
+)
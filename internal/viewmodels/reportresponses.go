@@ -0,0 +1,31 @@
+package viewmodels
+
+type (
+	PlayerReportResponse struct {
+		Player        string               `json:"player"`
+		Games         []GameReportResponse `json:"games"`
+		ByTimeControl []AggregateResponse  `json:"by_time_control"`
+		ByWeek        []AggregateResponse  `json:"by_week"`
+		SkippedGames  int                  `json:"skipped_games"`
+	}
+
+	GameReportResponse struct {
+		Site        string  `json:"site"`
+		Date        string  `json:"date"`
+		Week        string  `json:"week"`
+		TimeControl string  `json:"time_control"`
+		Color       string  `json:"color"`
+		Opponent    string  `json:"opponent"`
+		Result      string  `json:"result"`
+		Moves       int     `json:"moves"`
+		ACPL        float64 `json:"acpl"`
+		Accuracy    float64 `json:"accuracy"`
+	}
+
+	AggregateResponse struct {
+		Key      string  `json:"key"`
+		Games    int     `json:"games"`
+		ACPL     float64 `json:"acpl"`
+		Accuracy float64 `json:"accuracy"`
+	}
+)